@@ -1,10 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-
-	"github.com/rodaine/table"
 	"github.com/spf13/cobra"
 	"github.com/uthoplatforms/utho-cli/helper"
 )
@@ -12,29 +8,25 @@ import (
 var accountCmd = &cobra.Command{
 	Use:   "account",
 	Short: "Get account info",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
 var getAccountCmd = &cobra.Command{
 	Use:   "get",
 	Short: "Get account info",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		account, err := client.Account().Read()
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Id", "User Email", "Cloud Limit", "Total Cloud Instance", "K8s limit", "Currency", "Available Credit")
-		tbl.AddRow(account.ID, account.Email, account.Cloudlimit, account.TotalCloudservers, account.K8SLimit, account.Currency, account.Availablecredit)
-		tbl.Print()
+		return printResult(cmd, account, "ID", "Email", "Cloudlimit", "TotalCloudservers", "K8SLimit", "Currency", "Availablecredit")
 	},
 }
 
@@ -1,46 +1,285 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os"
+	"strings"
+	"time"
 
-	"github.com/rodaine/table"
 	"github.com/spf13/cobra"
 	"github.com/uthoplatforms/utho-cli/helper"
+	"github.com/uthoplatforms/utho-cli/printer"
+	"github.com/uthoplatforms/utho-go/utho"
 )
 
+var actionColumns = []string{"ID", "Action", "ResourceType", "ResourceID", "StartedAt", "CompletedAt", "Process"}
+
 var actionCmd = &cobra.Command{
 	Use:   "action",
 	Short: "Get action info",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
 var listActionCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List action info",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	Use:     "list",
+	Short:   "List action info",
+	Example: "uthoctl action list --resource-type cloud --since 24h --state pending",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter, err := newActionFilter(cmd)
+		if err != nil {
+			return helper.UsageError(err)
+		}
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		actions, err := client.Action().List()
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
+		}
+
+		return printResult(cmd, filter.apply(actions), actionColumns...)
+	},
+}
+
+var getActionCmd = &cobra.Command{
+	Use:     "get",
+	Short:   "Get action info",
+	Example: "uthoctl action get <action-id>",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+		action, err := findAction(client, args[0])
+		if err != nil {
+			return err
+		}
+
+		return printResult(cmd, action, append(actionColumns, "Status")...)
+	},
+}
+
+var watchActionCmd = &cobra.Command{
+	Use:     "watch",
+	Short:   "Print actions as they start and progress, until interrupted",
+	Example: "uthoctl action watch --resource-type kubernetes",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter, err := newActionFilter(cmd)
+		if err != nil {
+			return helper.UsageError(err)
 		}
+		p, err := newPrinter(cmd)
+		if err != nil {
+			return helper.UsageError(err)
+		}
+		p.KeepWidths = true
+		interval, _ := cmd.Flags().GetDuration("poll-interval")
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		seen := map[string]utho.Action{}
+		for first := true; ; first = false {
+			actions, err := client.Action().List()
+			if err != nil {
+				return err
+			}
+			var changed []utho.Action
+			for _, a := range filter.apply(actions) {
+				if old, ok := seen[a.ID]; ok && old == a {
+					continue
+				}
+				seen[a.ID] = a
+				changed = append(changed, a)
+			}
+			if err := printActions(p, changed, first); err != nil {
+				return err
+			}
 
-		tbl := table.New("ID", "Action", "ResourceType", "ResourceID", "StartedAt", "CompletedAt", "Process")
-		for _, action := range actions {
-			tbl.AddRow(action.ID, action.Action, action.ResourceType, action.ResourceID, action.StartedAt, action.CompletedAt, action.Process)
+			select {
+			case <-cmd.Context().Done():
+				return context.Cause(cmd.Context())
+			case <-time.After(interval):
+			}
 		}
-		tbl.Print()
 	},
 }
 
+var waitActionCmd = &cobra.Command{
+	Use:     "wait",
+	Short:   "Wait until an action completes and print its result",
+	Example: "uthoctl action wait <action-id> --wait-timeout 30m",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var client utho.Client
+		var last *utho.Action
+		err := helper.Wait(cmd.Context(), waitOptions(cmd), "action "+args[0], func(ctx context.Context) (string, helper.WaitState, error) {
+			if client == nil {
+				c, err := helper.NewUthoClient(ctx)
+				if err != nil {
+					return "", helper.WaitPending, err
+				}
+				client = c
+			}
+			action, err := findAction(client, args[0])
+			if err != nil {
+				return "", helper.WaitPending, err
+			}
+
+			last = action
+			if !helper.ActionCompleted(action.CompletedAt) {
+				return actionProgress(action), helper.WaitPending, nil
+			}
+			if helper.StatusState(action.Status) == helper.WaitFailed {
+				return action.Status, helper.WaitFailed, nil
+			}
+			return action.Status, helper.WaitDone, nil
+		})
+		if last != nil && (err == nil || errors.Is(err, helper.ErrFailed)) {
+			if err := printResult(cmd, last, append(actionColumns, "Status")...); err != nil {
+				return err
+			}
+		}
+		return err
+	},
+}
+
+// findAction returns the action id, as the API has no endpoint for a single
+// action.
+func findAction(client utho.Client, id string) (*utho.Action, error) {
+	actions, err := client.Action().List()
+	if err != nil {
+		return nil, err
+	}
+	for i := range actions {
+		if actions[i].ID == id {
+			return &actions[i], nil
+		}
+	}
+	return nil, helper.NotFoundError(fmt.Errorf("action %s not found", id))
+}
+
+// actionProgress describes a pending action, eg: "running 40%".
+func actionProgress(action *utho.Action) string {
+	status := strings.ToLower(action.Status)
+	if status == "" {
+		status = "pending"
+	}
+	if action.Process == "" {
+		return status
+	}
+	return fmt.Sprintf("%s %s%%", status, action.Process)
+}
+
+// printActions prints the actions found by one poll of action watch. Tables
+// only get headers for the first poll, other formats print an action at a
+// time so the output can be read as a stream.
+func printActions(p *printer.Printer, actions []utho.Action, first bool) error {
+	if p.Format != printer.FormatTable {
+		for _, a := range actions {
+			if err := p.Print(a); err != nil {
+				return printError(err)
+			}
+		}
+		return nil
+	}
+	if len(actions) == 0 && !first {
+		return nil
+	}
+	if err := p.Print(actions, actionColumns...); err != nil {
+		return printError(err)
+	}
+	p.NoHeaders = true
+	return nil
+}
+
+// actionFilter keeps the actions matching the filter flags.
+type actionFilter struct {
+	resourceType string
+	resourceID   string
+	since        time.Time
+	state        string
+}
+
+// Values of --state.
+const (
+	actionPending   = "pending"
+	actionCompleted = "completed"
+)
+
+func addActionFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("resource-type", "", "Only show actions on this type of resource, eg: cloud")
+	cmd.Flags().String("resource-id", "", "Only show actions on this resource")
+	cmd.Flags().String("since", "", `Only show actions started since this duration or time, eg: 1h, 2024-01-31 or "2024-01-31 15:04:05"`)
+	cmd.Flags().String("state", "", "Only show "+actionPending+" or "+actionCompleted+" actions")
+}
+
+func newActionFilter(cmd *cobra.Command) (actionFilter, error) {
+	var f actionFilter
+	f.resourceType, _ = cmd.Flags().GetString("resource-type")
+	f.resourceID, _ = cmd.Flags().GetString("resource-id")
+	f.state, _ = cmd.Flags().GetString("state")
+	f.state = strings.ToLower(f.state)
+	if f.state != "" && f.state != actionPending && f.state != actionCompleted {
+		return f, fmt.Errorf("invalid --state %q, use %s or %s", f.state, actionPending, actionCompleted)
+	}
+	if since, _ := cmd.Flags().GetString("since"); since != "" {
+		t, err := helper.ParseSince(since, time.Now())
+		if err != nil {
+			return f, err
+		}
+		f.since = t
+	}
+	return f, nil
+}
+
+func (f actionFilter) match(a utho.Action) bool {
+	if f.resourceType != "" && !strings.EqualFold(a.ResourceType, f.resourceType) {
+		return false
+	}
+	if f.resourceID != "" && a.ResourceID != f.resourceID {
+		return false
+	}
+	if !f.since.IsZero() {
+		// Actions with a start time that does not parse are kept.
+		started, err := time.ParseInLocation(helper.ActionTimeLayout, a.StartedAt, time.Local)
+		if err == nil && started.Before(f.since) {
+			return false
+		}
+	}
+	switch f.state {
+	case actionPending:
+		return !helper.ActionCompleted(a.CompletedAt)
+	case actionCompleted:
+		return helper.ActionCompleted(a.CompletedAt)
+	}
+	return true
+}
+
+func (f actionFilter) apply(actions []utho.Action) []utho.Action {
+	matched := []utho.Action{}
+	for _, a := range actions {
+		if f.match(a) {
+			matched = append(matched, a)
+		}
+	}
+	return matched
+}
+
 func init() {
 	rootCmd.AddCommand(actionCmd)
 	actionCmd.AddCommand(listActionCmd)
+	addActionFilterFlags(listActionCmd)
+	actionCmd.AddCommand(getActionCmd)
+	actionCmd.AddCommand(watchActionCmd)
+	addActionFilterFlags(watchActionCmd)
+	watchActionCmd.Flags().Duration("poll-interval", helper.DefaultPollInterval, "Time between two checks for new actions")
+	actionCmd.AddCommand(waitActionCmd)
+	addPollFlags(waitActionCmd)
 }
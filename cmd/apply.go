@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/uthoplatforms/utho-cli/helper"
+	"github.com/uthoplatforms/utho-cli/manifest"
+)
+
+var changeColumns = []string{"Kind", "Name", "Action", "ID"}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Create the resources of a manifest that are missing from the account",
+	Long: `Create the resources of a manifest that are missing from the account.
+
+Resources are created after those they reference, and the ones the account
+already has are left unchanged, so applying a manifest again does nothing.
+With --dry-run, the account is only read to print what would be created.`,
+	Example: "uthoctl apply -f env.yaml\nuthoctl apply -f env.yaml --dry-run",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := readManifest(cmd)
+		if err != nil {
+			return err
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		if dryRun() {
+			changes, err := manifest.Plan(client, m)
+			if err != nil {
+				return err
+			}
+			return printResult(cmd, changes, changeColumns...)
+		}
+
+		changes, err := manifest.Apply(client, m)
+		if err != nil {
+			// Show what was created before the failure.
+			if len(changes) > 0 {
+				printResult(cmd, changes, changeColumns...)
+			}
+			return err
+		}
+		return printResult(cmd, changes, changeColumns...)
+	},
+}
+
+// readManifest parses the manifest of the --filename flag, read from stdin
+// when it is "-".
+func readManifest(cmd *cobra.Command) (*manifest.Manifest, error) {
+	file, _ := cmd.Flags().GetString("filename")
+	var data []byte
+	var err error
+	if file == "-" {
+		file = "stdin"
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, helper.UsageError(err)
+	}
+
+	m, err := manifest.Parse(file, data)
+	if err != nil {
+		return nil, helper.UsageError(err)
+	}
+	return m, nil
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+	applyCmd.Flags().StringP("filename", "f", "", "Manifest file, - for stdin")
+	applyCmd.MarkFlagRequired("filename")
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/uthoplatforms/utho-cli/helper"
+)
+
+var auditColumns = []string{"Time", "User", "Host", "Context", "Command", "Args", "ResourceIDs", "Status"}
+
+// auditedVerbs are the names of the commands changing resources, which are
+// written to the audit log.
+var auditedVerbs = map[string]bool{"apply": true, "create": true, "delete": true, "enable": true, "disable": true}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Query the local log of the create, delete and other mutating commands run on this machine",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var listAuditCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List audit log entries, oldest first",
+	Example: "uthoctl audit list --since 24h\nuthoctl audit list --limit 20 -o json",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var since time.Time
+		if value, _ := cmd.Flags().GetString("since"); value != "" {
+			t, err := helper.ParseSince(value, time.Now())
+			if err != nil {
+				return helper.UsageError(err)
+			}
+			since = t
+		}
+		limit, _ := cmd.Flags().GetInt("limit")
+		if limit < 0 {
+			return helper.UsageError(fmt.Errorf("invalid --limit %d", limit))
+		}
+
+		entries, err := helper.ReadAuditLog(nil)
+		if err != nil {
+			return err
+		}
+		if !since.IsZero() {
+			kept := entries[:0]
+			for _, entry := range entries {
+				// Entries with a time that does not parse are kept.
+				t, err := time.Parse(time.RFC3339, entry.Time)
+				if err != nil || !t.Before(since) {
+					kept = append(kept, entry)
+				}
+			}
+			entries = kept
+		}
+		if limit > 0 && len(entries) > limit {
+			entries = entries[len(entries)-limit:]
+		}
+
+		return printResult(cmd, entries, auditColumns...)
+	},
+}
+
+var grepAuditCmd = &cobra.Command{
+	Use:     "grep",
+	Short:   "List the audit log entries matching a regular expression",
+	Example: "uthoctl audit grep 'instance delete'\nuthoctl audit grep -i '\"status\":\"failed\"'",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pattern := args[0]
+		if ignoreCase, _ := cmd.Flags().GetBool("ignore-case"); ignoreCase {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return helper.UsageError(fmt.Errorf("invalid pattern: %w", err))
+		}
+
+		entries, err := helper.ReadAuditLog(re.MatchString)
+		if err != nil {
+			return err
+		}
+
+		return printResult(cmd, entries, auditColumns...)
+	},
+}
+
+// recordAudit writes the run of cmd, which ended with err, to the audit log
+// when it is a mutating command. Failing to do so only prints a warning.
+func recordAudit(cmd *cobra.Command, err error) {
+	if !auditedVerbs[cmd.Name()] || cmd.Parent() == contextCmd || dryRun() {
+		return
+	}
+
+	flags := map[string]string{}
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed {
+			flags[f.Name] = f.Value.String()
+		}
+	})
+	entry := helper.NewAuditEntry(commandName(cmd), cmd.Flags().Args(), flags, err)
+	entry.ResourceIDs = append(entry.ResourceIDs, bulkAudit.ids...)
+	for _, r := range bulkAudit.results {
+		entry.Results = append(entry.Results, helper.AuditResult(r))
+	}
+	if err := helper.AppendAudit(entry); err != nil {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Warning: cannot write the audit log:", err)
+	}
+}
+
+// commandName is the path of cmd without the program name, eg: "instance
+// delete".
+func commandName(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.AddCommand(listAuditCmd)
+	listAuditCmd.Flags().String("since", "", `Only show entries since this duration or time, eg: 1h, 2024-01-31 or "2024-01-31 15:04:05"`)
+	listAuditCmd.Flags().Int("limit", 0, "Only show the last entries, 0 for all")
+	auditCmd.AddCommand(grepAuditCmd)
+	grepAuditCmd.Flags().BoolP("ignore-case", "i", false, "Match the pattern regardless of case")
+}
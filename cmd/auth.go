@@ -1,33 +1,171 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"syscall"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/uthoplatforms/utho-cli/helper"
 	"golang.org/x/term"
 )
 
 // authCmd represents the auth command
 var authCmd = &cobra.Command{
-	Use:   "auth",
-	Short: "Please authenticate uthoctl for use with your Utho account. You can generate a token in the control panel at https://console.utho.com/api",
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:     "auth",
+	Short:   "Please authenticate uthoctl for use with your Utho account. You can generate a token in the control panel at https://console.utho.com/api",
+	Example: "uthoctl auth\nuthoctl auth --context prod\necho $TOKEN | uthoctl auth --token-stdin",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		var token string
-		for {
-			fmt.Fprint(os.Stderr, "Enter your api token: ")
-			b, _ := term.ReadPassword(int(syscall.Stdin))
-			token = string(b)
-			if token != "" {
-				break
+		tokenStdin, _ := cmd.Flags().GetBool("token-stdin")
+		if tokenStdin {
+			b, err := io.ReadAll(cmd.InOrStdin())
+			if err != nil {
+				return fmt.Errorf("error reading token: %w", err)
 			}
+			token = strings.TrimSpace(string(b))
+			if token == "" {
+				return helper.UsageError(errors.New("no token given on stdin"))
+			}
+		} else {
+			for {
+				fmt.Fprint(cmd.ErrOrStderr(), "Enter your api token: ")
+				b, err := term.ReadPassword(int(syscall.Stdin))
+				fmt.Fprintln(cmd.ErrOrStderr())
+				if err != nil {
+					return helper.UsageError(fmt.Errorf("cannot read the token from the terminal, use --token-stdin: %w", err))
+				}
+				token = strings.TrimSpace(string(b))
+				if token != "" {
+					break
+				}
+			}
+		}
+
+		client, err := helper.NewUthoClientWithToken(cmd.Context(), token)
+		if err != nil {
+			return err
+		}
+		account, err := client.Account().Read()
+		if err != nil {
+			return fmt.Errorf("invalid token: %w", err)
 		}
-		helper.SaveToken(token)
+
+		fmt.Fprintln(cmd.OutOrStdout(), "Authenticated as", account.Email)
+		return helper.SaveToken(token)
+	},
+}
+
+type authStatus struct {
+	Context string `json:"context"`
+	Source  string `json:"source"`
+	Token   string `json:"token"`
+	Email   string `json:"email"`
+	// Status tells whether the API accepted the token: valid, invalid, or
+	// unknown when it could not be asked.
+	Status string `json:"status"`
+}
+
+var statusAuthCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the active context, where the token comes from and the account it belongs to",
+	Long: `Show the active context, where the token comes from, the masked token and
+the email of the account it belongs to. When the API refuses the token,
+the status says so and the command exits with a non-zero code.`,
+	Example: "uthoctl auth status",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, source, err := helper.ResolveToken()
+		if err != nil {
+			return err
+		}
+		cfg, err := helper.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		client, err := helper.NewUthoClientWithToken(cmd.Context(), token)
+		if err != nil {
+			return err
+		}
+
+		// The status is printed even when the token does not work, to show
+		// which one is used, then the command fails.
+		status := authStatus{
+			Context: cfg.ContextName(),
+			Source:  source,
+			Token:   helper.MaskToken(token),
+			Status:  "valid",
+		}
+		account, readErr := client.Account().Read()
+		switch {
+		case readErr == nil:
+			status.Email = account.Email
+		case helper.ExitCode(readErr) == helper.ExitAuth:
+			status.Status, readErr = "invalid", fmt.Errorf("invalid token: %w", readErr)
+		default:
+			status.Status, readErr = "unknown", fmt.Errorf("cannot check the token: %w", readErr)
+		}
+		if err := printResult(cmd, status, "Context", "Source", "Token", "Email", "Status"); err != nil {
+			return err
+		}
+		return readErr
+	},
+}
+
+var logoutAuthCmd = &cobra.Command{
+	Use:     "logout",
+	Short:   "Remove the token of the active context from the config file",
+	Example: "uthoctl auth logout\nuthoctl auth logout --context prod",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := helper.RemoveToken(); err != nil {
+			return err
+		}
+		if viper.GetString("token") != "" || os.Getenv("UTHO_TOKEN") != "" {
+			fmt.Fprintln(cmd.ErrOrStderr(), "A token is still given with --token or UTHO_TOKEN")
+		}
+		return nil
+	},
+}
+
+var migrateAuthCmd = &cobra.Command{
+	Use:     "migrate",
+	Short:   "Move the tokens of every context to another credential store",
+	Example: "uthoctl auth migrate --store file\nuthoctl auth migrate --store helper --helper docker-credential-pass",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, _ := cmd.Flags().GetString("store")
+		credentialHelper, _ := cmd.Flags().GetString("helper")
+
+		cfg, err := helper.LoadConfig()
+		if err != nil {
+			return err
+		}
+		moved, err := cfg.MigrateCredentials(store, credentialHelper)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Moved %d token(s) to the %s credential store\n", moved, store)
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(authCmd)
+	authCmd.Flags().Bool("token-stdin", false, "Read the token from stdin")
+
+	authCmd.AddCommand(statusAuthCmd)
+	authCmd.AddCommand(logoutAuthCmd)
+
+	authCmd.AddCommand(migrateAuthCmd)
+	migrateAuthCmd.Flags().String("store", "", "Credential store: "+strings.Join(helper.CredentialStores, ", "))
+	migrateAuthCmd.Flags().String("helper", "", "Credential helper executable, required with --store helper")
+	migrateAuthCmd.MarkFlagRequired("store")
 }
@@ -1,10 +1,8 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
+	"strconv"
 
-	"github.com/rodaine/table"
 	"github.com/spf13/cobra"
 	"github.com/uthoplatforms/utho-cli/helper"
 	"github.com/uthoplatforms/utho-go/utho"
@@ -13,8 +11,8 @@ import (
 var autoscalingCmd = &cobra.Command{
 	Use:   "autoscaling",
 	Short: "Use this command to manage autoscalings.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -22,13 +20,7 @@ var createAutoscalingCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create an autoscaling Policy.",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
+	RunE: func(cmd *cobra.Command, args []string) error {
 		osDiskSize, _ := cmd.Flags().GetInt("os_disk_size")
 		dcslug, _ := cmd.Flags().GetString("dcslug")
 		minsize, _ := cmd.Flags().GetString("minsize")
@@ -47,8 +39,7 @@ var createAutoscalingCmd = &cobra.Command{
 
 		publicIpEnabled, err := helper.StringToBool(publicIpEnabledStr)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return helper.UsageError(err)
 		}
 
 		params := utho.CreateAutoScalingParams{
@@ -71,15 +62,25 @@ var createAutoscalingCmd = &cobra.Command{
 			Stackimage:         stackimage,
 			TargetGroups:       targetGroups,
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		autoscaling, err := client.AutoScaling().Create(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Autoscaling Name", "Autoscaling Id", "Status")
-		tbl.AddRow(args[0], autoscaling.ID, autoscaling.Status)
-		tbl.Print()
+		if err := printResult(cmd, autoscaling, "ID", "Status"); err != nil {
+			return err
+		}
+		id := strconv.Itoa(autoscaling.ID)
+		return waitFor(cmd, "auto scaling group "+id, waitReady, autoscalingStatus(id))
 	},
 }
 
@@ -87,82 +88,86 @@ var getAutoscalingCmd = &cobra.Command{
 	Use:   "get",
 	Short: "Get autoscaling info",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		autoscaling, err := client.AutoScaling().Read(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name", "Dcslug", "Minsize", "Maxsize", "Image", "Status")
-		tbl.AddRow(autoscaling.ID, autoscaling.Name, autoscaling.Dcslug, autoscaling.Minsize, autoscaling.Maxsize, autoscaling.Image, autoscaling.Status)
-		tbl.Print()
+		return printResult(cmd, autoscaling, "ID", "Name", "Dcslug", "Minsize", "Maxsize", "Image", "Status")
 	},
 }
 
 var listAutoscalingCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List autoscaling info",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		autoscalings, err := client.AutoScaling().List()
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name", "Dcslug", "Minsize", "Maxsize", "Image", "Status")
-		for _, autoscaling := range autoscalings {
-			tbl.AddRow(autoscaling.ID, autoscaling.Name, autoscaling.Dcslug, autoscaling.Minsize, autoscaling.Maxsize, autoscaling.Image, autoscaling.Status)
-		}
-		tbl.Print()
+		return printResult(cmd, autoscalings, "ID", "Name", "Dcslug", "Minsize", "Maxsize", "Image", "Status")
 	},
 }
 
 var deleteAutoscalingCmd = &cobra.Command{
 	Use:     "delete",
 	Short:   "delete an autoscaling from your account.",
-	Example: "uthoctl autoscaling delete <autoscaling-id> <autoscaling-name>",
+	Example: "uthoctl autoscaling delete <autoscaling-id> <autoscaling-name>\nuthoctl autoscaling delete --selector 'name=test-*,plan=10045'",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ID: args[0], Name: args[1]})
+		}
+
+		if err := helper.Confirm(); err != nil {
+			return err
 		}
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		autoscaling, err := client.AutoScaling().Delete(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + autoscaling.Status)
+		if err := printResult(cmd, autoscaling, "Status"); err != nil {
+			return err
+		}
+		return waitFor(cmd, "auto scaling group "+args[0], waitGone, autoscalingStatus(args[0]))
 	},
 }
 
+// autoscalingStatus reads the status of the auto scaling group id, for --wait.
+func autoscalingStatus(id string) statusReader {
+	return func(client utho.Client) (string, error) {
+		group, err := client.AutoScaling().Read(id)
+		if err != nil {
+			return "", err
+		}
+		return group.Status, nil
+	}
+}
+
 // Policy
 var policyCmd = &cobra.Command{
 	Use:   "policy",
 	Short: "Use this command to manage autoscalings policy.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -171,13 +176,7 @@ var createPolicyCmd = &cobra.Command{
 	Short:   "Create an autoscaling Policy.",
 	Example: "uthoctl autoscaling policy create <autoscaling-id> <policy-name>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
+	RunE: func(cmd *cobra.Command, args []string) error {
 		policyType, _ := cmd.Flags().GetString("type")
 		compare, _ := cmd.Flags().GetString("compare")
 		value, _ := cmd.Flags().GetString("value")
@@ -197,38 +196,40 @@ var createPolicyCmd = &cobra.Command{
 			Product:   product,
 			Productid: args[0],
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		policy, err := client.AutoScaling().CreatePolicy(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Policy Name", "Autoscaling Id", "Status")
-		tbl.AddRow(args[1], policy.ID, policy.Status)
-		tbl.Print()
+		return printResult(cmd, policy, "ID", "Status")
 	},
 }
 
 var getPolicyCmd = &cobra.Command{
 	Use:     "get",
 	Short:   "Get autoscaling policy info",
-	Example: "uthoctl autoscaling policy get <autoscaling-id> <policy-id",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	Example: "uthoctl autoscaling policy get <autoscaling-id> <policy-id>",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		policy, err := client.AutoScaling().ReadPolicy(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Productid", "Name", "Type", "Value", "Status", "Cloudid", "Maxsize", "Minsize")
-		tbl.AddRow(policy.ID, policy.Productid, policy.Name, policy.Type, policy.Value, policy.Status, policy.Cloudid, policy.Maxsize, policy.Minsize)
-		tbl.Print()
+		return printResult(cmd, policy, "ID", "Productid", "Name", "Type", "Value", "Status", "Cloudid", "Maxsize", "Minsize")
 	},
 }
 
@@ -236,24 +237,19 @@ var listPolicyCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List autoscaling policy",
 	Example: "uthoctl autoscaling policy list <autoscaling-id>",
-	Run: func(cmd *cobra.Command, args []string) {
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		policies, err := client.AutoScaling().ListPolicies(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Autoscaling id", "Name", "Type", "Value", "Status", "Cloudid", "Maxsize", "Minsize")
-		for _, policy := range policies {
-			tbl.AddRow(policy.ID, policy.Productid, policy.Name, policy.Type, policy.Value, policy.Status, policy.Cloudid, policy.Maxsize, policy.Minsize)
-		}
-		tbl.Print()
+		return printResult(cmd, policies, "ID", "Productid", "Name", "Type", "Value", "Status", "Cloudid", "Maxsize", "Minsize")
 	},
 }
 
@@ -262,26 +258,26 @@ var deletePolicyCmd = &cobra.Command{
 	Short:   "delete an autoscaling policy from your account.",
 	Example: "uthoctl autoscaling policy delete <policy-id>",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ID: args[0]})
 		}
 
-		client, err := helper.NewUthoClient()
+		if err := helper.Confirm(); err != nil {
+			return err
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		autoscaling, err := client.AutoScaling().DeletePolicy(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + autoscaling.Status)
+		return printResult(cmd, autoscaling, "Status")
 	},
 }
 
@@ -289,8 +285,8 @@ var deletePolicyCmd = &cobra.Command{
 var scheduleCmd = &cobra.Command{
 	Use:   "schedule",
 	Short: "Use this command to manage autoscalings Schedule Policy.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -299,13 +295,7 @@ var createScheduleCmd = &cobra.Command{
 	Short:   "Create an autoscaling schedule schedule.",
 	Example: "uthoctl autoscaling schedule create <autoscaling-id> <schedule-name>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
+	RunE: func(cmd *cobra.Command, args []string) error {
 		desiredsize, _ := cmd.Flags().GetString("desiredsize")
 		recurrence, _ := cmd.Flags().GetString("recurrence")
 		startDate, _ := cmd.Flags().GetString("start_date")
@@ -317,63 +307,60 @@ var createScheduleCmd = &cobra.Command{
 			Recurrence:    recurrence,
 			StartDate:     startDate,
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		schedule, err := client.AutoScaling().CreateSchedule(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Schedule Name", "Schedule Id", "Status")
-		tbl.AddRow(args[1], schedule.ID, schedule.Status)
-		tbl.Print()
+		return printResult(cmd, schedule, "ID", "Status")
 	},
 }
 
 var getScheduleCmd = &cobra.Command{
 	Use:     "get",
 	Short:   "Get autoscaling schedule info",
-	Example: "uthoctl autoscaling schedule get <autoscaling-id> <schedule-id",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	Example: "uthoctl autoscaling schedule get <autoscaling-id> <schedule-id>",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		schedule, err := client.AutoScaling().ReadSchedule(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Group id", "Name", "Desiredsize", "Recurrence", "StartDate", "Status", "Timezone")
-		tbl.AddRow(schedule.ID, schedule.Groupid, schedule.Name, schedule.Desiredsize, schedule.Recurrence, schedule.StartDate, schedule.Status, schedule.Timezone)
-		tbl.Print()
+		return printResult(cmd, schedule, "ID", "Groupid", "Name", "Desiredsize", "Recurrence", "StartDate", "Status", "Timezone")
 	},
 }
 
 var listScheduleCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List autoscaling policy",
-	Example: "uthoctl autoscaling policy list <autoscaling-id>",
-	Run: func(cmd *cobra.Command, args []string) {
+	Example: "uthoctl autoscaling schedule list <autoscaling-id>",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		schedules, err := client.AutoScaling().ListSchedules(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Group id", "Name", "Desiredsize", "Recurrence", "StartDate", "Timezone")
-		for _, schedule := range schedules {
-			tbl.AddRow(schedule.ID, schedule.Groupid, schedule.Name, schedule.Desiredsize, schedule.Recurrence, schedule.StartDate, schedule.Timezone)
-		}
-		tbl.Print()
+		return printResult(cmd, schedules, "ID", "Groupid", "Name", "Desiredsize", "Recurrence", "StartDate", "Timezone")
 	},
 }
 
@@ -382,26 +369,26 @@ var deleteScheduleCmd = &cobra.Command{
 	Short:   "delete an autoscaling policy from your account.",
 	Example: "uthoctl autoscaling schedule delete <autoscaling-id> <schedule-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ParentID: args[0], ID: args[1]})
 		}
 
-		client, err := helper.NewUthoClient()
+		if err := helper.Confirm(); err != nil {
+			return err
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		autoscaling, err := client.AutoScaling().DeleteSchedule(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + autoscaling.Status)
+		return printResult(cmd, autoscaling, "Status")
 	},
 }
 
@@ -409,8 +396,8 @@ var deleteScheduleCmd = &cobra.Command{
 var autoscalingLoadbalancerCmd = &cobra.Command{
 	Use:   "loadbalancer",
 	Short: "Use this command to manage autoscalings Load Balancer.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -419,49 +406,45 @@ var createAutoscalingLoadbalancerCmd = &cobra.Command{
 	Short:   "Create an autoscaling loadbalancer.",
 	Example: "uthoctl autoscaling loadbalancer create <autoscaling-id> <loadbalancer-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
+	RunE: func(cmd *cobra.Command, args []string) error {
 		params := utho.CreateAutoScalingLoadbalancerParams{
 			AutoScalingId:  args[0],
 			LoadbalancerId: args[1],
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		loadbalancer, err := client.AutoScaling().CreateLoadbalancer(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("AutoScaling Loadbalancer Id", "Status")
-		tbl.AddRow(loadbalancer.ID, loadbalancer.Status)
-		tbl.Print()
+		return printResult(cmd, loadbalancer, "ID", "Status")
 	},
 }
 
 var getAutoscalingLoadbalancerCmd = &cobra.Command{
 	Use:     "get",
 	Short:   "Get autoscaling loadbalancer info",
-	Example: "uthoctl autoscaling loadbalancer get <autoscaling-id> <loadbalancer-id",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	Example: "uthoctl autoscaling loadbalancer get <autoscaling-id> <loadbalancer-id>",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		loadbalancer, err := client.AutoScaling().ReadLoadbalancer(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name", "IP")
-		tbl.AddRow(loadbalancer.ID, loadbalancer.Name, loadbalancer.IP)
-		tbl.Print()
+		return printResult(cmd, loadbalancer, "ID", "Name", "IP")
 	},
 }
 
@@ -469,24 +452,19 @@ var listAutoscalingLoadbalancerCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List autoscaling Loadbalancer",
 	Example: "uthoctl autoscaling loadbalancer list <autoscaling-id>",
-	Run: func(cmd *cobra.Command, args []string) {
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		loadbalancers, err := client.AutoScaling().ListLoadbalancers(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name", "IP")
-		for _, loadbalancer := range loadbalancers {
-			tbl.AddRow(loadbalancer.ID, loadbalancer.Name, loadbalancer.IP)
-		}
-		tbl.Print()
+		return printResult(cmd, loadbalancers, "ID", "Name", "IP")
 	},
 }
 
@@ -495,26 +473,26 @@ var deleteAutoscalingLoadbalancerCmd = &cobra.Command{
 	Short:   "delete an autoscaling policy from your account.",
 	Example: "uthoctl autoscaling loadbalancer delete <autoscaling-id> <loadbalancer-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ParentID: args[0], ID: args[1]})
+		}
+
+		if err := helper.Confirm(); err != nil {
+			return err
 		}
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		loadbalancer, err := client.AutoScaling().DeleteLoadbalancer(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + loadbalancer.Status)
+		return printResult(cmd, loadbalancer, "Status")
 	},
 }
 
@@ -522,8 +500,8 @@ var deleteAutoscalingLoadbalancerCmd = &cobra.Command{
 var securitygroupCmd = &cobra.Command{
 	Use:   "securitygroup",
 	Short: "Use this command to manage autoscalings Security Group.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -532,49 +510,45 @@ var createSecuritygroupCmd = &cobra.Command{
 	Short:   "Create an autoscaling securitygroup.",
 	Example: "uthoctl autoscaling securitygroup create <autoscaling-id> <securitygroup-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
+	RunE: func(cmd *cobra.Command, args []string) error {
 		params := utho.CreateAutoScalingSecurityGroupParams{
 			AutoScalingId:              args[0],
 			AutoScalingSecurityGroupId: args[1],
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		securitygroup, err := client.AutoScaling().CreateSecurityGroup(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Securitygroup Name", "Securitygroup Id", "Status")
-		tbl.AddRow(args[1], securitygroup.ID, securitygroup.Status)
-		tbl.Print()
+		return printResult(cmd, securitygroup, "ID", "Status")
 	},
 }
 
 var getSecuritygroupCmd = &cobra.Command{
 	Use:     "get",
 	Short:   "Get autoscaling securitygroup info",
-	Example: "uthoctl autoscaling securitygroup get <autoscaling-id> <securitygroup-id",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	Example: "uthoctl autoscaling securitygroup get <autoscaling-id> <securitygroup-id>",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		securitygroup, err := client.AutoScaling().ReadSecurityGroup(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name")
-		tbl.AddRow(securitygroup.ID, securitygroup.Name)
-		tbl.Print()
+		return printResult(cmd, securitygroup, "ID", "Name")
 	},
 }
 
@@ -582,24 +556,19 @@ var listSecuritygroupCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List autoscaling securitygroup",
 	Example: "uthoctl autoscaling securitygroup list <autoscaling-id>",
-	Run: func(cmd *cobra.Command, args []string) {
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		securitygroups, err := client.AutoScaling().ListSecurityGroups(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name")
-		for _, securitygroup := range securitygroups {
-			tbl.AddRow(securitygroup.ID, securitygroup.Name)
-		}
-		tbl.Print()
+		return printResult(cmd, securitygroups, "ID", "Name")
 	},
 }
 
@@ -608,26 +577,26 @@ var deleteSecuritygroupCmd = &cobra.Command{
 	Short:   "delete an autoscaling سecuritygroup from your account.",
 	Example: "uthoctl autoscaling securitygroup delete <autoscaling-id> <securitygroup-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ParentID: args[0], ID: args[1]})
 		}
 
-		client, err := helper.NewUthoClient()
+		if err := helper.Confirm(); err != nil {
+			return err
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		securitygroup, err := client.AutoScaling().DeleteSecurityGroup(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + securitygroup.Status)
+		return printResult(cmd, securitygroup, "Status")
 	},
 }
 
@@ -635,8 +604,8 @@ var deleteSecuritygroupCmd = &cobra.Command{
 var autoscalingtargetgroupCmd = &cobra.Command{
 	Use:   "targetgroup",
 	Short: "Use this command to manage autoscalings Target Group.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -645,74 +614,65 @@ var createAutoscalingTargetgroupCmd = &cobra.Command{
 	Short:   "Create an autoscaling targetgroup.",
 	Example: "uthoctl autoscaling targetgroup create <autoscaling-id> <targetgroup-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
+	RunE: func(cmd *cobra.Command, args []string) error {
 		params := utho.CreateAutoScalingTargetgroupParams{
 			AutoScalingId:            args[0],
 			AutoScalingTargetgroupId: args[1],
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		targetgroup, err := client.AutoScaling().CreateTargetgroup(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Targetgroup Name", "Targetgroup Id", "Status")
-		tbl.AddRow(args[1], targetgroup.ID, targetgroup.Status)
-		tbl.Print()
+		return printResult(cmd, targetgroup, "ID", "Status")
 	},
 }
 
 var getAutoscalingTargetgroupCmd = &cobra.Command{
 	Use:     "get",
 	Short:   "Get autoscaling targetgroup info",
-	Example: "uthoctl autoscaling targetgroup get <autoscaling-id> <targetgroup-id",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	Example: "uthoctl autoscaling targetgroup get <autoscaling-id> <targetgroup-id>",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		targetgroup, err := client.AutoScaling().ReadTargetgroup(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name", "Protocol", "Port")
-		tbl.AddRow(targetgroup.ID, targetgroup.Name, targetgroup.Protocol, targetgroup.Port)
-		tbl.Print()
+		return printResult(cmd, targetgroup, "ID", "Name", "Protocol", "Port")
 	},
 }
 
 var listAutoscalingTargetgroupCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List autoscaling policy",
-	Example: "uthoctl autoscaling policy list <autoscaling-id>",
-	Run: func(cmd *cobra.Command, args []string) {
+	Example: "uthoctl autoscaling targetgroup list <autoscaling-id>",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		targetgroups, err := client.AutoScaling().ListTargetgroups(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name", "Protocol", "Port")
-		for _, targetgroup := range targetgroups {
-			tbl.AddRow(targetgroup.ID, targetgroup.Name, targetgroup.Protocol, targetgroup.Port)
-		}
-		tbl.Print()
+		return printResult(cmd, targetgroups, "ID", "Name", "Protocol", "Port")
 	},
 }
 
@@ -721,26 +681,26 @@ var deleteAutoscalingTargetgroupCmd = &cobra.Command{
 	Short:   "delete an autoscaling Targetgroup from your account.",
 	Example: "uthoctl autoscaling targetgroup delete <autoscaling-id> <targetgroup-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ParentID: args[0], ID: args[1]})
+		}
+
+		if err := helper.Confirm(); err != nil {
+			return err
 		}
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		targetgroup, err := client.AutoScaling().DeleteTargetgroup(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + targetgroup.Status)
+		return printResult(cmd, targetgroup, "Status")
 	},
 }
 
@@ -748,6 +708,7 @@ func init() {
 	rootCmd.AddCommand(autoscalingCmd)
 
 	autoscalingCmd.AddCommand(createAutoscalingCmd)
+	addWaitFlags(createAutoscalingCmd, "auto scaling group is active")
 	createAutoscalingCmd.Flags().Int("os_disk_size", 0, "")
 	createAutoscalingCmd.Flags().String("dcslug", "", "")
 	createAutoscalingCmd.Flags().String("minsize", "", "")
@@ -756,21 +717,35 @@ func init() {
 	createAutoscalingCmd.Flags().String("planid", "", "")
 	createAutoscalingCmd.Flags().String("planname", "", "")
 	createAutoscalingCmd.Flags().String("instance_templateid", "", "")
-	createAutoscalingCmd.Flags().String("public_ip_enabled", "", "")
+	createAutoscalingCmd.Flags().String("public_ip_enabled", "false", "")
 	createAutoscalingCmd.Flags().String("vpc", "", "")
 	createAutoscalingCmd.Flags().String("load_balancers", "", "")
 	createAutoscalingCmd.Flags().String("security_groups", "", "")
 	createAutoscalingCmd.Flags().String("stackid", "", "")
 	createAutoscalingCmd.Flags().String("stackimage", "", "")
 	createAutoscalingCmd.Flags().String("target_groups", "", "")
+	resolveFlagNames(createAutoscalingCmd, "vpc", helper.KindVPC)
+	createAutoscalingCmd.MarkFlagRequired("dcslug")
+	createAutoscalingCmd.MarkFlagRequired("planid")
+	createAutoscalingCmd.MarkFlagRequired("minsize")
+	createAutoscalingCmd.MarkFlagRequired("maxsize")
+	createAutoscalingCmd.MarkFlagRequired("desiredsize")
 
 	autoscalingCmd.AddCommand(getAutoscalingCmd)
+	resolveNames(getAutoscalingCmd, helper.KindAutoscaling)
 	autoscalingCmd.AddCommand(listAutoscalingCmd)
 	autoscalingCmd.AddCommand(deleteAutoscalingCmd)
+	resolveNames(deleteAutoscalingCmd, helper.KindAutoscaling)
+	addWaitFlags(deleteAutoscalingCmd, "auto scaling group is deleted")
+	addSelector(deleteAutoscalingCmd, autoscalingSelection, "delete", func(client utho.Client, c candidate) error {
+		_, err := client.AutoScaling().Delete(c.ID, c.Name)
+		return err
+	})
 
 	// Policy
 	autoscalingCmd.AddCommand(policyCmd)
 	policyCmd.AddCommand(createPolicyCmd)
+	resolveNames(createPolicyCmd, helper.KindAutoscaling)
 	createPolicyCmd.Flags().String("dcslug", "", "Provide Zone dcslug eg: innoida")
 	createPolicyCmd.Flags().String("type", "", "")
 	createPolicyCmd.Flags().String("compare", "", "")
@@ -781,38 +756,57 @@ func init() {
 	createPolicyCmd.Flags().String("product", "", "")
 
 	policyCmd.AddCommand(getPolicyCmd)
+	resolveNames(getPolicyCmd, helper.KindAutoscaling)
 	policyCmd.AddCommand(listPolicyCmd)
+	resolveNames(listPolicyCmd, helper.KindAutoscaling)
 	policyCmd.AddCommand(deletePolicyCmd)
 
 	// Schedule
 	autoscalingCmd.AddCommand(scheduleCmd)
 	scheduleCmd.AddCommand(createScheduleCmd)
+	resolveNames(createScheduleCmd, helper.KindAutoscaling)
 	createScheduleCmd.Flags().String("desiredsize", "", "")
 	createScheduleCmd.Flags().String("recurrence", "", "")
 	createScheduleCmd.Flags().String("start_date", "", "")
+	createScheduleCmd.MarkFlagRequired("desiredsize")
 
 	scheduleCmd.AddCommand(getScheduleCmd)
+	resolveNames(getScheduleCmd, helper.KindAutoscaling)
 	scheduleCmd.AddCommand(listScheduleCmd)
+	resolveNames(listScheduleCmd, helper.KindAutoscaling)
 	scheduleCmd.AddCommand(deleteScheduleCmd)
+	resolveNames(deleteScheduleCmd, helper.KindAutoscaling)
 
 	// Loadbalancer
 	autoscalingCmd.AddCommand(autoscalingLoadbalancerCmd)
 	autoscalingLoadbalancerCmd.AddCommand(createAutoscalingLoadbalancerCmd)
+	resolveNames(createAutoscalingLoadbalancerCmd, helper.KindAutoscaling, helper.KindLoadbalancer)
 	autoscalingLoadbalancerCmd.AddCommand(getAutoscalingLoadbalancerCmd)
+	resolveNames(getAutoscalingLoadbalancerCmd, helper.KindAutoscaling, helper.KindLoadbalancer)
 	autoscalingLoadbalancerCmd.AddCommand(listAutoscalingLoadbalancerCmd)
+	resolveNames(listAutoscalingLoadbalancerCmd, helper.KindAutoscaling)
 	autoscalingLoadbalancerCmd.AddCommand(deleteAutoscalingLoadbalancerCmd)
+	resolveNames(deleteAutoscalingLoadbalancerCmd, helper.KindAutoscaling, helper.KindLoadbalancer)
 
 	// Securitygroup
 	autoscalingCmd.AddCommand(securitygroupCmd)
 	securitygroupCmd.AddCommand(createSecuritygroupCmd)
+	resolveNames(createSecuritygroupCmd, helper.KindAutoscaling)
 	securitygroupCmd.AddCommand(getSecuritygroupCmd)
+	resolveNames(getSecuritygroupCmd, helper.KindAutoscaling)
 	securitygroupCmd.AddCommand(listSecuritygroupCmd)
+	resolveNames(listSecuritygroupCmd, helper.KindAutoscaling)
 	securitygroupCmd.AddCommand(deleteSecuritygroupCmd)
+	resolveNames(deleteSecuritygroupCmd, helper.KindAutoscaling)
 
 	// Targetgroup
 	autoscalingCmd.AddCommand(autoscalingtargetgroupCmd)
 	autoscalingtargetgroupCmd.AddCommand(createAutoscalingTargetgroupCmd)
+	resolveNames(createAutoscalingTargetgroupCmd, helper.KindAutoscaling, helper.KindTargetGroup)
 	autoscalingtargetgroupCmd.AddCommand(getAutoscalingTargetgroupCmd)
+	resolveNames(getAutoscalingTargetgroupCmd, helper.KindAutoscaling, helper.KindTargetGroup)
 	autoscalingtargetgroupCmd.AddCommand(listAutoscalingTargetgroupCmd)
+	resolveNames(listAutoscalingTargetgroupCmd, helper.KindAutoscaling)
 	autoscalingtargetgroupCmd.AddCommand(deleteAutoscalingTargetgroupCmd)
+	resolveNames(deleteAutoscalingTargetgroupCmd, helper.KindAutoscaling, helper.KindTargetGroup)
 }
@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/uthoplatforms/utho-cli/helper"
+	"github.com/uthoplatforms/utho-cli/printer"
+	"github.com/uthoplatforms/utho-go/utho"
+)
+
+// candidate is a resource a --selector can match.
+type candidate struct {
+	ID   string
+	Name string
+	// Dcslug, Statuses, Plan and CreatedAt are left empty by the kinds
+	// whose resources the API does not report them for.
+	Dcslug    string
+	Statuses  []string
+	Plan      string
+	CreatedAt string
+}
+
+// selectable is a kind of resources commands can select with --selector.
+type selectable struct {
+	// what names the kind in messages, eg: "instance".
+	what string
+	// keys are the selector keys the kind supports besides name.
+	keys []string
+	list func(client utho.Client) ([]candidate, error)
+}
+
+// Selector keys, see selector.
+const (
+	keyName          = "name"
+	keyDcslug        = "dcslug"
+	keyStatus        = "status"
+	keyPlan          = "plan"
+	keyCreatedBefore = "created-before"
+)
+
+var (
+	instanceSelection = &selectable{
+		what: "instance",
+		keys: []string{keyDcslug, keyStatus, keyCreatedBefore},
+		list: func(client utho.Client) ([]candidate, error) {
+			instances, err := client.CloudInstances().List()
+			return candidates(instances, err, func(i utho.CloudInstance) candidate {
+				return candidate{ID: i.ID, Name: i.Hostname, Dcslug: i.Dclocation.Dc, Statuses: []string{i.Status, i.Powerstatus}, CreatedAt: i.CreatedAt}
+			})
+		},
+	}
+	vpcSelection = &selectable{
+		what: "VPC",
+		keys: []string{keyDcslug},
+		list: func(client utho.Client) ([]candidate, error) {
+			vpcs, err := client.Vpc().List()
+			return candidates(vpcs, err, func(v utho.Vpc) candidate {
+				return candidate{ID: v.ID, Name: v.Name, Dcslug: v.Dcslug}
+			})
+		},
+	}
+	firewallSelection = &selectable{
+		what: "firewall",
+		keys: []string{keyCreatedBefore},
+		list: func(client utho.Client) ([]candidate, error) {
+			firewalls, err := client.Firewall().List()
+			return candidates(firewalls, err, func(fw utho.Firewall) candidate {
+				return candidate{ID: fw.ID, Name: fw.Name, CreatedAt: fw.CreatedAt}
+			})
+		},
+	}
+	loadbalancerSelection = &selectable{
+		what: "load balancer",
+		keys: []string{keyStatus, keyCreatedBefore},
+		list: func(client utho.Client) ([]candidate, error) {
+			loadbalancers, err := client.Loadbalancers().List()
+			return candidates(loadbalancers, err, func(lb utho.Loadbalancer) candidate {
+				return candidate{ID: lb.ID, Name: lb.Name, Statuses: []string{lb.Status}, CreatedAt: lb.CreatedAt}
+			})
+		},
+	}
+	clusterSelection = &selectable{
+		what: "cluster",
+		keys: []string{keyDcslug, keyStatus, keyCreatedBefore},
+		list: func(client utho.Client) ([]candidate, error) {
+			clusters, err := client.Kubernetes().List()
+			return candidates(clusters, err, func(k utho.K8s) candidate {
+				return candidate{ID: k.ID, Name: k.Hostname, Dcslug: k.Dcslug, Statuses: []string{k.Status, k.Powerstatus}, CreatedAt: k.CreatedAt}
+			})
+		},
+	}
+	targetgroupSelection = &selectable{
+		what: "target group",
+		keys: []string{keyCreatedBefore},
+		list: func(client utho.Client) ([]candidate, error) {
+			targetgroups, err := client.TargetGroup().List()
+			return candidates(targetgroups, err, func(tg utho.TargetGroup) candidate {
+				return candidate{ID: tg.ID, Name: tg.Name, CreatedAt: tg.CreatedAt}
+			})
+		},
+	}
+	autoscalingSelection = &selectable{
+		what: "auto scaling group",
+		keys: []string{keyDcslug, keyStatus, keyPlan, keyCreatedBefore},
+		list: func(client utho.Client) ([]candidate, error) {
+			groups, err := client.AutoScaling().List()
+			return candidates(groups, err, func(g utho.Groups) candidate {
+				return candidate{ID: g.ID, Name: g.Name, Dcslug: g.Dcslug, Statuses: []string{g.Status}, Plan: g.Planid, CreatedAt: g.CreatedAt}
+			})
+		},
+	}
+)
+
+func candidates[T any](items []T, err error, f func(T) candidate) ([]candidate, error) {
+	if err != nil {
+		return nil, err
+	}
+	selected := make([]candidate, len(items))
+	for i, item := range items {
+		selected[i] = f(item)
+	}
+	return selected, nil
+}
+
+// selector is a parsed --selector: a glob on names and the values the other
+// keys must have, all of which a resource must match.
+type selector struct {
+	name, dcslug, status, plan string
+	createdBefore              time.Time
+}
+
+// parseSelector parses the key=value terms of --selector for the resources
+// of s.
+func parseSelector(terms []string, s *selectable, now time.Time) (*selector, error) {
+	sel := &selector{}
+	for _, term := range terms {
+		key, value, ok := strings.Cut(term, "=")
+		key = strings.TrimSpace(key)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid selector %q, use key=value, eg: name=web-*", term)
+		}
+		if key != keyName && !contains(s.keys, key) {
+			return nil, fmt.Errorf("%ss cannot be selected by %q, use %s", s.what, key, strings.Join(append([]string{keyName}, s.keys...), ", "))
+		}
+
+		switch key {
+		case keyName:
+			if _, err := path.Match(value, ""); err != nil {
+				return nil, fmt.Errorf("invalid name pattern %q: %w", value, err)
+			}
+			sel.name = value
+		case keyDcslug:
+			sel.dcslug = value
+		case keyStatus:
+			sel.status = value
+		case keyPlan:
+			sel.plan = value
+		case keyCreatedBefore:
+			t, err := helper.ParseSince(value, now)
+			if err != nil {
+				return nil, fmt.Errorf("invalid created-before %q, use a duration such as 720h or a time such as 2024-01-31", value)
+			}
+			sel.createdBefore = t
+		}
+	}
+	return sel, nil
+}
+
+// match reports whether c matches every key of sel. Statuses match
+// regardless of case, and resources whose creation time is unknown are
+// never created before a time.
+func (sel *selector) match(c candidate) bool {
+	if sel.name != "" {
+		if ok, _ := path.Match(sel.name, c.Name); !ok {
+			return false
+		}
+	}
+	if sel.dcslug != "" && c.Dcslug != sel.dcslug || sel.plan != "" && c.Plan != sel.plan {
+		return false
+	}
+	if sel.status != "" {
+		matched := false
+		for _, status := range c.Statuses {
+			matched = matched || strings.EqualFold(status, sel.status)
+		}
+		if !matched {
+			return false
+		}
+	}
+	if !sel.createdBefore.IsZero() {
+		created, err := time.ParseInLocation(helper.ActionTimeLayout, c.CreatedAt, time.Local)
+		if err != nil || !created.Before(sel.createdBefore) {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// bulkAction is what a command does to one of the resources it selected.
+type bulkAction func(client utho.Client, c candidate) error
+
+// bulkResult is the outcome of a bulkAction.
+type bulkResult struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// bulkAudit holds the resources the command selected with --selector and
+// the result of the action on each, which recordAudit logs.
+var bulkAudit struct {
+	ids     []string
+	results []bulkResult
+}
+
+// addSelector lets cmd act on the resources of s matching --selector (or
+// its alias --filter) instead of those given as arguments: verb describes
+// the action in the confirmation, eg: "delete", and action performs it.
+func addSelector(cmd *cobra.Command, s *selectable, verb string, action bulkAction) {
+	cmd.Flags().StringSlice("selector", nil, fmt.Sprintf("Select the %ss to %s instead of giving one, eg: name=web-*,dcslug=innoida (alias --filter, keys: %s)",
+		s.what, verb, strings.Join(append([]string{keyName}, s.keys...), ", ")))
+	cmd.Flags().Int("parallel", 4, "Number of selected resources processed at once")
+	cmd.Flags().SetNormalizeFunc(func(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "filter" {
+			name = "selector"
+		}
+		return pflag.NormalizedName(name)
+	})
+
+	args, run := cmd.Args, cmd.RunE
+	cmd.Args = func(cmd *cobra.Command, a []string) error {
+		if !cmd.Flags().Changed("selector") {
+			return args(cmd, a)
+		}
+		if len(a) > 0 {
+			return fmt.Errorf("--selector replaces the arguments, got %s", strings.Join(a, " "))
+		}
+		return nil
+	}
+	cmd.RunE = func(cmd *cobra.Command, a []string) error {
+		if !cmd.Flags().Changed("selector") {
+			return run(cmd, a)
+		}
+		return runSelected(cmd, s, verb, action)
+	}
+}
+
+// runSelected runs action on the resources of s matching --selector, at
+// most --parallel at once, after a single confirmation listing them, then
+// prints the result of each. It fails if any action did.
+func runSelected(cmd *cobra.Command, s *selectable, verb string, action bulkAction) error {
+	terms, _ := cmd.Flags().GetStringSlice("selector")
+	parallel, _ := cmd.Flags().GetInt("parallel")
+	if parallel < 1 {
+		return helper.UsageError(fmt.Errorf("invalid --parallel %d, must be at least 1", parallel))
+	}
+	if wait, _ := cmd.Flags().GetBool("wait"); wait {
+		return helper.UsageError(fmt.Errorf("--wait cannot be used with --selector"))
+	}
+	sel, err := parseSelector(terms, s, time.Now())
+	if err != nil {
+		return helper.UsageError(err)
+	}
+
+	client, err := helper.NewUthoClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+	all, err := s.list(client)
+	if err != nil {
+		return err
+	}
+	var selected []candidate
+	for _, c := range all {
+		if sel.match(c) {
+			selected = append(selected, c)
+		}
+	}
+	if len(selected) == 0 {
+		return helper.NotFoundError(fmt.Errorf("no %s matches the selector %s", s.what, strings.Join(terms, ",")))
+	}
+
+	if dryRun() {
+		requests := make([]dryRunRequest, len(selected))
+		for i, c := range selected {
+			requests[i] = dryRunRequest{Command: commandName(cmd), ID: c.ID, Name: c.Name, Dcslug: c.Dcslug}
+		}
+		return printResult(cmd, requests, printer.Fields(dryRunRequest{})...)
+	}
+
+	items := make([]string, len(selected))
+	for i, c := range selected {
+		bulkAudit.ids = append(bulkAudit.ids, c.ID)
+		items[i] = c.ID
+		if c.Name != "" && c.Name != c.ID {
+			items[i] = fmt.Sprintf("%s (%s)", c.Name, c.ID)
+		}
+	}
+	if err := helper.ConfirmBulk(verb, s.what, items); err != nil {
+		return err
+	}
+
+	results := make([]bulkResult, len(selected))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, c := range selected {
+		wg.Add(1)
+		go func(i int, c candidate) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i] = bulkResult{ID: c.ID, Name: c.Name, Result: "success"}
+			err := cmd.Context().Err()
+			if err == nil {
+				err = action(client, c)
+			}
+			if err != nil {
+				results[i].Result, results[i].Error = "failed", err.Error()
+			}
+		}(i, c)
+	}
+	wg.Wait()
+	bulkAudit.results = results
+
+	if err := printResult(cmd, results, "ID", "Name", "Result", "Error"); err != nil {
+		return err
+	}
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d %ss failed", failed, len(results), s.what)
+	}
+	return nil
+}
@@ -0,0 +1,743 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/uthoplatforms/utho-cli/helper"
+	"github.com/uthoplatforms/utho-cli/internal/fakeapi"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of the command tests")
+
+// step is one uthoctl run of a scenario. Arguments are split on spaces,
+// single quotes group words. stdin is fed to the command and its prompts.
+type step struct {
+	args  string
+	stdin string
+}
+
+// result is what a run printed and its exit code.
+type result struct {
+	stdout, stderr string
+	code           int
+}
+
+// newTestServer starts a fake API and points uthoctl at it with a fresh HOME,
+// so no config file of the machine is read.
+func newTestServer(t *testing.T) *fakeapi.Server {
+	t.Helper()
+	srv := fakeapi.New()
+	t.Cleanup(srv.Close)
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("UTHO_TOKEN", srv.Token)
+	t.Setenv("UTHO_API_URL", srv.URL)
+	for _, env := range []string{"UTHO_CONFIG", "UTHO_CONTEXT", "UTHO_DEBUG", "UTHO_RETRIES", "UTHO_RETRY_MUTATING", "UTHO_AUDIT_LOG", "UTHO_PASSPHRASE", "XDG_STATE_HOME"} {
+		t.Setenv(env, "")
+	}
+	return srv
+}
+
+// runCommand runs uthoctl with args and returns its output.
+func runCommand(t *testing.T, stdin string, args ...string) result {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+
+	resetFlags(rootCmd)
+	resetContexts(rootCmd)
+	rootCmd.SetArgs(args)
+	rootCmd.SetIn(strings.NewReader(stdin))
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	helper.Stdin = strings.NewReader(stdin)
+	isTerminal := helper.StdinIsTerminal
+	helper.StdinIsTerminal = func() bool { return stdin != "" }
+	helper.Stdout = &stdout
+	helper.Stderr = &stderr
+	helper.LogOutput = &stderr
+	defer func() {
+		rootCmd.SetArgs(nil)
+		rootCmd.SetIn(nil)
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		helper.Stdin = os.Stdin
+		helper.StdinIsTerminal = isTerminal
+		helper.Stdout = os.Stdout
+		helper.Stderr = os.Stderr
+		helper.LogOutput = os.Stderr
+	}()
+
+	code := execute()
+	return result{stdout: stdout.String(), stderr: stderr.String(), code: code}
+}
+
+// splitArgs splits a command line on spaces, keeping single-quoted text as
+// one argument.
+func splitArgs(line string) []string {
+	var args []string
+	var cur strings.Builder
+	quoted, started := false, false
+	for _, r := range line {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+			started = true
+		case r == ' ' && !quoted:
+			if started {
+				args = append(args, cur.String())
+				cur.Reset()
+				started = false
+			}
+		default:
+			cur.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		args = append(args, cur.String())
+	}
+	return args
+}
+
+// runScenario runs steps in order against srv and compares a transcript of
+// their output with testdata/<name>.golden. Lines written to stderr are
+// prefixed with "! " and non-zero exit codes are noted after each run. The
+// address of srv and the HOME directory are replaced by fixed names.
+func runScenario(t *testing.T, srv *fakeapi.Server, name string, steps []step) {
+	t.Helper()
+	base := strings.TrimSuffix(srv.URL, "/v2/")
+
+	var transcript strings.Builder
+	for _, s := range steps {
+		res := runCommand(t, s.stdin, splitArgs(s.args)...)
+
+		fmt.Fprintf(&transcript, "$ uthoctl %s\n", s.args)
+		if s.stdin != "" {
+			fmt.Fprintf(&transcript, "< %s\n", strings.TrimSuffix(s.stdin, "\n"))
+		}
+		transcript.WriteString(res.stdout)
+		if res.stdout != "" && !strings.HasSuffix(res.stdout, "\n") {
+			transcript.WriteString("\n")
+		}
+		for _, line := range strings.SplitAfter(res.stderr, "\n") {
+			if line != "" {
+				transcript.WriteString("! " + strings.TrimSuffix(line, "\n") + "\n")
+			}
+		}
+		if res.code != 0 {
+			fmt.Fprintf(&transcript, "[exit %d]\n", res.code)
+		}
+		transcript.WriteString("\n")
+	}
+	got := strings.NewReplacer(base, "http://fakeapi", os.Getenv("HOME"), "$HOME").Replace(transcript.String())
+
+	golden := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("%v (run go test ./cmd -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s (run go test ./cmd -update to accept it)\n--- got\n%s--- want\n%s", golden, got, want)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"account", []step{
+			{args: "account get"},
+			{args: "account get -o json"},
+		}},
+		{"instance", []step{
+			{args: "instance create web --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045 --billingcycle monthly"},
+			{args: "instance create db --dcslug innoida --image ubuntu-22.04-x86_64"},
+			{args: "instance list"},
+			{args: "instance get 1001 --columns ID,Hostname,Status,Image.Image,Dclocation.Dc"},
+			{args: "instance snapshot create 1001"},
+			{args: "instance get 1001 -o jsonpath='{range .items[0].snapshots[*]}{.id} {.name}{\"\\n\"}{end}'"},
+			{args: "instance get 1001 -o jsonpath={.items[0].x}"},
+			{args: "instance snapshot delete 1001 1003", stdin: "y\n"},
+			{args: "instance backup enable 1001"},
+			{args: "instance backup disable 1001", stdin: "y\n"},
+			{args: "instance delete 1001", stdin: "n\n"},
+			{args: "instance delete 1001", stdin: "y\n"},
+			{args: "instance get 1001"},
+			{args: "instance list --no-headers"},
+		}},
+		{"audit", []step{
+			{args: "audit list"},
+			{args: "instance create web --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045 --root_password s3cret"},
+			{args: "instance delete 1001"},
+			{args: "instance delete 1001 --yes"},
+			{args: "instance delete 1001 --dry-run"},
+			{args: "instance list"},
+			{args: "firewall delete 1009 -y"},
+			{args: "audit list --columns Context,Command,Args,Flags,ResourceIDs,Status,ExitCode"},
+			{args: "audit list --limit 1 --columns Command,Error"},
+			{args: "audit grep delete --columns Command,Args,Status"},
+			{args: "audit grep -i '\"STATUS\":\"aborted\"' --columns Command,Error"},
+			{args: "audit grep '('"},
+		}},
+		{"confirm", []step{
+			{args: "instance create web --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045"},
+			{args: "instance create db --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045"},
+			{args: "instance delete 1001"},
+			{args: "instance delete 1001 --yes"},
+			{args: "context set confirm typed"},
+			{args: "instance delete 1003", stdin: "y\n"},
+			{args: "instance delete 1003", stdin: "db\n"},
+			{args: "instance list"},
+			{args: "instance create web-2 --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045"},
+			{args: "instance create web-3 --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045"},
+			{args: "instance delete --selector name=web*", stdin: "y\n"},
+			{args: "instance delete --selector name=web*", stdin: "2\n"},
+			{args: "instance list"},
+		}},
+		{"dryrun", []step{
+			{args: "instance create web --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045 --dry-run"},
+			{args: "instance create web --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045 --dry-run -o json"},
+			{args: "instance create web --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045 --root_password s3cret --dry-run -o yaml"},
+			{args: "autoscaling create web --dcslug innoida --planid 10045 --minsize 1 --maxsize 3 --desiredsize 1 --stackimage ubuntu-22.04-x86_64 --public_ip_enabled maybe --dry-run"},
+			{args: "loadbalancer route create 1003 1005 1007 --route_condition true --target_groups 1 --dry-run -o yaml"},
+			{args: "instance delete 1001 --dry-run"},
+			{args: "kubernetes delete 1001 --dry-run -o yaml"},
+			{args: "objectstorage delete innoida backups --dry-run -o json"},
+			{args: "instance create web --dry-run"},
+			{args: "vpc create private --dcslug innoida --dry-run"},
+			{args: "context set dcslug innoida"},
+			{args: "loadbalancer create lb --dry-run"},
+			{args: "instance list"},
+		}},
+		{"resolve", []step{
+			{args: "instance create web --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045"},
+			{args: "instance create db --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045"},
+			{args: "instance create db --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045"},
+			{args: "instance get web --columns ID,Hostname"},
+			{args: "instance get db"},
+			{args: "instance get cache"},
+			{args: "firewall create edge"},
+			{args: "firewall firewallrule create edge --type incoming --service SSH --protocol tcp --port 22 --addresses 0.0.0.0/0"},
+			{args: "firewall firewallrule list edge"},
+			{args: "instance create app --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045 --firewall edge --dry-run -o json"},
+			{args: "vpc create private --dcslug innoida --planid 1008 --network 10.210.100.0 --size 24"},
+			{args: "vpc delete private --dry-run"},
+		}},
+		{"apply", []step{
+			{args: "apply -f testdata/env.yaml --dry-run"},
+			{args: "apply -f testdata/env.yaml"},
+			{args: "apply -f testdata/env.yaml"},
+			{args: "firewall firewallrule list web"},
+			{args: "domain records list example.com"},
+			{args: "loadbalancer route list web"},
+			{args: "audit list --columns Command,Flags,ResourceIDs,Status"},
+			{args: "apply -f - --dry-run -o json", stdin: "resources:\n- {kind: firewall, name: web}\n- {kind: firewallrule, name: ssh, firewall: web, type: incoming, protocol: tcp, port: 22, addresses: 0.0.0.0/0}\n"},
+			{args: "apply -f -", stdin: "resources:\n- {kind: instance, name: a, dcslug: innoida, image: ubuntu, planid: 1, firewall: missing}\n"},
+			{args: "apply -f -", stdin: "resources:\n- {kind: frontend, name: a, loadbalancer: web, proto: http, port: 80}\n- {kind: acl, name: b, loadbalancer: web, frontend: c, condition_type: http_host}\n"},
+			{args: "apply -f -", stdin: "resources:\n- {kind: firewall, name: '${firewall.b}'}\n- {kind: firewall, name: b, dcslug: innoida}\n"},
+			{args: "apply -f -", stdin: "resources:\n- {kind: vpc, name: a, dcslug: '${vpc.b}', planid: 1, network: 10.0.0.0, size: 24}\n- {kind: vpc, name: b, dcslug: '${vpc.a}', planid: 1, network: 10.0.1.0, size: 24}\n"},
+			{args: "apply -f testdata/missing.yaml"},
+			{args: "apply -f -", stdin: "resources:\n- {kind: vpc, name: web, dcslug: innoida, network: 10.0.0.0, size: 24}\n"},
+			{args: "apply -f -", stdin: "resources:\n- {kind: firewall, name: fw1}\n- {kind: vpc, name: v, dcslug: innoida, network: 10.0.2.0, size: 24}\n"},
+			{args: "firewall list --columns ID,Name"},
+		}},
+		{"export", []step{
+			{args: "apply -f testdata/env.yaml --columns Kind,Name,Action"},
+			{args: "apply -f - --columns Kind,Name,Action", stdin: "resources:\n" +
+				"- {kind: kubernetes, name: k8s, dcslug: delhi, vpc: private}\n" +
+				"- {kind: autoscaling, name: workers, dcslug: delhi, planid: 10045, minsize: 1, maxsize: 3, desiredsize: 2}\n" +
+				"- {kind: policy, name: cpu, autoscaling: workers, type: cpu, compare: '>', value: 80, adjust: 1, period: 5m, cooldown: 300}\n" +
+				"- {kind: schedule, name: nightly, autoscaling: workers, desiredsize: 1, recurrence: daily, start_date: 2024-01-01}\n"},
+			{args: "domain records create example.com --type MX --hostname @ --value mail.example.com --priority 10"},
+			{args: "export"},
+			{args: "export --kind record,policy"},
+			{args: "export --location delhi --kind instance,kubernetes,autoscaling"},
+			{args: "context set dcslug delhi"},
+			{args: "export --kind instance"},
+			{args: "context unset dcslug"},
+			{args: "export --kind server"},
+			{args: "export --format terraform"},
+			{args: "export --format terraform --kind record"},
+			{args: "export --format terraform --kind frontend"},
+			{args: "export --format hcl"},
+		}},
+		{"diff", []step{
+			{args: "diff -f testdata/env.yaml"},
+			{args: "apply -f testdata/env.yaml --columns Kind,Name,Action"},
+			{args: "diff -f testdata/env.yaml"},
+			{args: "firewall firewallrule create web --type incoming --service SSH --protocol tcp --port 22 --addresses 0.0.0.0/0"},
+			{args: "domain records delete example.com 1024 -y"},
+			{args: "domain records create example.com --type A --hostname www --value 203.0.113.99 --ttl 300"},
+			{args: "diff -f testdata/env.yaml --color always"},
+			{args: "diff -f testdata/env.yaml -o json"},
+			{args: "diff -f testdata/env.yaml --color rainbow"},
+		}},
+		{"selector", []step{
+			{args: "instance create test-1 --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045"},
+			{args: "instance create test-2 --dcslug delhi --image ubuntu-22.04-x86_64 --planid 10045"},
+			{args: "instance create prod-1 --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045"},
+			{args: "instance delete --selector name=test-* --dry-run"},
+			{args: "instance delete --selector 'created-before=2024-01-01 00:00:02' --dry-run"},
+			{args: "instance delete --selector name=test-*", stdin: "n\n"},
+			{args: "instance backup enable --filter dcslug=innoida --selector status=active", stdin: "y\n"},
+			{args: "instance snapshot create --selector name=prod-* -y -o json"},
+			{args: "instance delete --selector name=test-* --parallel 1", stdin: "y\n"},
+			{args: "instance list --columns ID,Hostname"},
+			{args: "instance delete --selector name=test-* -y"},
+			{args: "instance delete --selector plan=10045"},
+			{args: "instance delete --selector created-before=soon"},
+			{args: "instance delete --selector 'name=[' -y"},
+			{args: "instance delete 1005 --selector name=prod-*"},
+			{args: "instance delete --selector name=prod-* --parallel 0"},
+			{args: "targetgroup create test-a --protocol HTTP --port 80"},
+			{args: "targetgroup create test+b --protocol HTTP --port 80"},
+			{args: "targetgroup delete --selector name=test* -y"},
+			{args: "targetgroup list --columns ID,Name"},
+			{args: "audit grep selector --columns Command,Flags,ResourceIDs,Status"},
+			{args: "audit list --limit 1 -o jsonpath='{range .items[0].results[*]}{.id} {.name} {.result} {.error}{\"\\n\"}{end}'"},
+		}},
+		{"firewall", []step{
+			{args: "firewall create web"},
+			{args: "firewall firewallrule create 1001 --type incoming --service SSH --protocol tcp --port 22 --addresses 0.0.0.0/0"},
+			{args: "firewall firewallrule create 1001 --type incoming --protocol tcp"},
+			{args: "firewall get 1001"},
+			{args: "firewall list"},
+			{args: "firewall firewallrule get 1001 1003"},
+			{args: "firewall firewallrule get 1001"},
+			{args: "firewall firewallrule list 1001"},
+			{args: "firewall firewallrule list"},
+			{args: "firewall firewallrule delete 1001 1003", stdin: "y\n"},
+			{args: "firewall firewallrule list 1001"},
+			{args: "firewall delete 1001", stdin: "y\n"},
+			{args: "firewall get 1001"},
+		}},
+		{"domain", []step{
+			{args: "domain create example.com"},
+			{args: "domain create example.com"},
+			{args: "domain records create example.com --type A --hostname www --value 203.0.113.10"},
+			{args: "domain get example.com"},
+			{args: "domain list"},
+			{args: "domain records list example.com"},
+			{args: "domain records delete example.com 1002", stdin: "y\n"},
+			{args: "domain delete example.com", stdin: "y\n"},
+			{args: "domain get example.com"},
+		}},
+		{"vpc", []step{
+			{args: "vpc create private --dcslug innoida --planid 1008 --network 10.210.100.0 --size 24"},
+			{args: "vpc create broken --dcslug innoida"},
+			{args: "vpc get 1001"},
+			{args: "vpc list"},
+			{args: "vpc delete 1001", stdin: "y\n"},
+			{args: "vpc get 1001"},
+			{args: "vpc create legacy --dcslug innoida --planid 1008 --network 10.210.101.0 --Size 24 --Billing monthly --dry-run"},
+		}},
+		{"loadbalancer", []step{
+			{args: "instance create web --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045"},
+			{args: "loadbalancer create lb --dcslug innoida --type application"},
+			{args: "loadbalancer frontend create 1003 http --proto http --port 80 --algorithm roundrobin"},
+			{args: "loadbalancer acl create 1003 api --condition_type http_path --frontend_id 1005 --value /api"},
+			{args: "loadbalancer backend create 1003 1005 1001 --port 8080"},
+			{args: "loadbalancer route create 1003 1005 1007 --route_condition true --target_groups 1"},
+			{args: "loadbalancer get 1003"},
+			{args: "loadbalancer list"},
+			{args: "loadbalancer frontend get 1003 1005"},
+			{args: "loadbalancer frontend list 1003"},
+			{args: "loadbalancer acl get 1003 1007"},
+			{args: "loadbalancer acl list 1003"},
+			{args: "loadbalancer backend get 1003 1009"},
+			{args: "loadbalancer backend list 1003"},
+			{args: "loadbalancer route get 1003 1011"},
+			{args: "loadbalancer route list 1003"},
+			{args: "loadbalancer route delete 1003 1011", stdin: "y\n"},
+			{args: "loadbalancer backend delete 1003 1009", stdin: "y\n"},
+			{args: "loadbalancer acl delete 1003 1007", stdin: "y\n"},
+			{args: "loadbalancer frontend delete 1003 1005", stdin: "y\n"},
+			{args: "loadbalancer delete 1003", stdin: "y\n"},
+			{args: "loadbalancer get 1003"},
+			{args: "loadbalancer create legacy --dcslug innoida --route_condition true --target_groups 1 --dry-run"},
+		}},
+		{"targetgroup", []step{
+			{args: "targetgroup create web --protocol HTTP --port 80 --health_check_path / --health_check_protocol HTTP"},
+			{args: "targetgroup target create 1001 --backend_protocol HTTP --backend_port 8080 --ip 203.0.113.10"},
+			{args: "targetgroup get 1001"},
+			{args: "targetgroup list"},
+			{args: "targetgroup target get 1001 1003"},
+			{args: "targetgroup target list 1001"},
+			{args: "targetgroup target delete 1001 1003", stdin: "y\n"},
+			{args: "targetgroup delete 1001 other", stdin: "y\n"},
+			{args: "targetgroup delete 1001 web", stdin: "y\n"},
+			{args: "targetgroup list"},
+		}},
+		{"kubernetes", []step{
+			{args: "kubernetes create prod --dcslug innoida --cluster_version 1.27.0"},
+			{args: "loadbalancer create lb --dcslug innoida"},
+			{args: "firewall create k8s"},
+			{args: "targetgroup create web --protocol HTTP --port 80"},
+			{args: "kubernetes get 1001"},
+			{args: "kubernetes list"},
+			{args: "kubernetes loadbalancer create 1001 1003"},
+			{args: "kubernetes securitygroup create 1001 1005"},
+			{args: "kubernetes targetgroup create 1001 1007"},
+			{args: "kubernetes loadbalancer get 1001 1003"},
+			{args: "kubernetes loadbalancer list 1001"},
+			{args: "kubernetes securitygroup get 1001 1005"},
+			{args: "kubernetes securitygroup list 1001"},
+			{args: "kubernetes targetgroup get 1001 1007"},
+			{args: "kubernetes targetgroup list 1001"},
+			{args: "kubernetes loadbalancer delete 1001 1003", stdin: "y\n"},
+			{args: "kubernetes securitygroup delete 1001 1005", stdin: "y\n"},
+			{args: "kubernetes targetgroup delete 1001 1007", stdin: "y\n"},
+			{args: "kubernetes delete 1001", stdin: "y\n"},
+			{args: "kubernetes get 1001"},
+			{args: "kubernetes create prod --cluster_label legacy --dcslug innoida --dry-run -o json"},
+		}},
+		{"autoscaling", []step{
+			{args: "autoscaling create web --dcslug innoida --planid 10045 --minsize 1 --maxsize 3 --desiredsize 1 --stackimage ubuntu-22.04-x86_64"},
+			{args: "loadbalancer create lb --dcslug innoida"},
+			{args: "firewall create asg"},
+			{args: "targetgroup create web --protocol HTTP --port 80"},
+			{args: "autoscaling get 1001"},
+			{args: "autoscaling list"},
+			{args: "autoscaling policy create 1001 cpu --type cpu --compare above --value 80 --adjust 1 --period 5m --cooldown 300"},
+			{args: "autoscaling policy get 1001 1009"},
+			{args: "autoscaling policy list 1001"},
+			{args: "autoscaling schedule create 1001 nightly --desiredsize 2 --recurrence daily --start_date 2024-01-02"},
+			{args: "autoscaling schedule get 1001 1011"},
+			{args: "autoscaling schedule list 1001"},
+			{args: "autoscaling loadbalancer create 1001 1003"},
+			{args: "autoscaling loadbalancer get 1001 1003"},
+			{args: "autoscaling loadbalancer list 1001"},
+			{args: "autoscaling securitygroup create 1001 1005"},
+			{args: "autoscaling securitygroup get 1001 1005"},
+			{args: "autoscaling securitygroup list 1001"},
+			{args: "autoscaling targetgroup create 1001 1007"},
+			{args: "autoscaling targetgroup get 1001 1007"},
+			{args: "autoscaling targetgroup list 1001"},
+			{args: "autoscaling targetgroup delete 1001 1007", stdin: "y\n"},
+			{args: "autoscaling securitygroup delete 1001 1005", stdin: "y\n"},
+			{args: "autoscaling loadbalancer delete 1001 1003", stdin: "y\n"},
+			{args: "autoscaling schedule delete 1001 1011", stdin: "y\n"},
+			{args: "autoscaling policy delete 1009", stdin: "y\n"},
+			{args: "autoscaling delete 1001 web", stdin: "y\n"},
+			{args: "autoscaling list"},
+		}},
+		{"objectstorage", []step{
+			{args: "objectstorage create assets --dcslug innoida --billing monthly --size 250"},
+			{args: "objectstorage get innoida assets"},
+			{args: "objectstorage list innoida"},
+			{args: "objectstorage list inbangalore"},
+			{args: "objectstorage accesskey create innoida ci"},
+			{args: "objectstorage accesskey get innoida ci"},
+			{args: "objectstorage accesskey list innoida"},
+			{args: "objectstorage delete innoida assets", stdin: "y\n"},
+			{args: "objectstorage get innoida assets"},
+			{args: "objectstorage create legacy --dcslug innoida --Billing monthly --Size 250 --dry-run -o json"},
+		}},
+		{"action", []step{
+			{args: "action list"},
+			{args: "firewall create web"},
+			{args: "domain create example.com"},
+			{args: "firewall delete 1001 --show-action", stdin: "y\n"},
+			{args: "action list"},
+			{args: "action list --resource-type firewall"},
+			{args: "action list --resource-id example.com -o json"},
+			{args: "action list --since '2024-01-01 00:00:03'"},
+			{args: "action list --state pending"},
+			{args: "action list --state done"},
+			{args: "action list --since yesterday"},
+			{args: "action get 1004"},
+			{args: "action get 42"},
+			{args: "action wait 1002 --poll-interval 1ms"},
+		}},
+		{"auth", []step{
+			{args: "auth --token-stdin", stdin: "wrong-token\n"},
+			{args: "auth --token-stdin", stdin: fakeapi.DefaultToken + "\n"},
+			{args: "auth status -o yaml"},
+			{args: "auth status --token wrong-token -o yaml"},
+			{args: "auth logout"},
+			{args: "auth logout"},
+		}},
+		{"context", []step{
+			{args: "context set dcslug innoida"},
+			{args: "context set output yaml"},
+			{args: "context set confirm typed"},
+			{args: "context set yes true"},
+			{args: "context set token secret"},
+			{args: "context set dry-run true"},
+			{args: "context set api-url http://example.com"},
+			{args: "context set root_password s3cret"},
+			{args: "context set selector name=*"},
+			{args: "context set dcslgu innoida"},
+			{args: "vpc create private --planid 1008 --network 10.210.100.0 --size 24 --dry-run"},
+			{args: "context unset output"},
+			{args: "context unset dcslug"},
+			{args: "vpc create private --planid 1008 --network 10.210.100.0 --size 24 --dry-run"},
+		}},
+		{"errors", []step{
+			{args: "firewall delete"},
+			{args: "firewall firewallrule get 1001"},
+			{args: "loadbalancer route create 1 2 3 --bogus x"},
+			{args: "instance get 42"},
+			{args: "instance list --token wrong-token"},
+			{args: "instance list -o xml"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer(t)
+			if tt.name == "auth" {
+				t.Setenv("UTHO_TOKEN", "")
+			}
+			runScenario(t, srv, tt.name, tt.steps)
+		})
+	}
+}
+
+func TestAuthMigrate(t *testing.T) {
+	srv := newTestServer(t)
+	t.Setenv("UTHO_TOKEN", "")
+	t.Setenv("UTHO_PASSPHRASE", "correct horse")
+	runScenario(t, srv, "auth-migrate", []step{
+		{args: "auth --token-stdin", stdin: fakeapi.DefaultToken + "\n"},
+		{args: "auth migrate --store plaintext"},
+		{args: "auth migrate --store file"},
+		{args: "auth migrate --store file"},
+		{args: "auth status"},
+		{args: "context list"},
+		{args: "auth migrate --store helper"},
+		{args: "auth migrate --store vault"},
+		{args: "auth migrate --store plaintext"},
+		{args: "auth status -o yaml"},
+	})
+}
+
+// TestUnsafeContextDefaults checks that the defaults context set refuses
+// are ignored when found in a config file.
+func TestUnsafeContextDefaults(t *testing.T) {
+	srv := newTestServer(t)
+	config := "current-context: default\ncontexts:\n  default:\n    defaults:\n      yes: \"true\"\n      dcslug: innoida\n"
+	file := filepath.Join(os.Getenv("HOME"), ".config", "uthoctl.yaml")
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	runScenario(t, srv, "context-unsafe", []step{
+		{args: "firewall create web"},
+		{args: "firewall delete web"},
+		{args: "loadbalancer create lb --dry-run"},
+	})
+}
+
+func TestRecordReplay(t *testing.T) {
+	srv := newTestServer(t)
+	cassette := t.TempDir()
+
+	commands := [][]string{
+		{"loadbalancer", "create", "lb", "--dcslug", "innoida"},
+		{"loadbalancer", "list"},
+		{"loadbalancer", "get", "1001"},
+		{"loadbalancer", "get", "9999"},
+	}
+	var recorded []result
+	for _, args := range commands {
+		recorded = append(recorded, runCommand(t, "", append(args, "--record", cassette)...))
+	}
+
+	files, _ := filepath.Glob(filepath.Join(cassette, "*.json"))
+	if len(files) != len(commands) {
+		t.Fatalf("recorded %d exchanges, want %d", len(files), len(commands))
+	}
+	for _, file := range files {
+		data, _ := os.ReadFile(file)
+		if strings.Contains(string(data), srv.Token) {
+			t.Errorf("token not scrubbed from %s:\n%s", file, data)
+		}
+	}
+
+	// Replay with no token and no server.
+	srv.Close()
+	t.Setenv("UTHO_TOKEN", "")
+	for i, args := range commands {
+		got := runCommand(t, "", append(args, "--replay", cassette)...)
+		if got != recorded[i] {
+			t.Errorf("%v replayed %+v, recorded %+v", args, got, recorded[i])
+		}
+	}
+
+	got := runCommand(t, "", "firewall", "list", "--replay", cassette)
+	if got.code != helper.ExitNetwork || !strings.Contains(got.stderr, "no recorded response for GET /v2/firewall") {
+		t.Errorf("request missing from the cassette: %+v", got)
+	}
+	got = runCommand(t, "", "loadbalancer", "list", "--replay", cassette, "--record", cassette)
+	if got.code != helper.ExitUsage {
+		t.Errorf("--record with --replay: %+v", got)
+	}
+}
+
+func TestRecordRedactsSecrets(t *testing.T) {
+	newTestServer(t)
+	cassette := t.TempDir()
+
+	got := runCommand(t, "", "instance", "create", "web", "--dcslug", "innoida", "--image", "ubuntu-22.04-x86_64", "--planid", "10045",
+		"--root_password", "s3cret-root", "--record", cassette)
+	if got.code != 0 || !strings.Contains(got.stdout, "fake-password") {
+		t.Fatalf("instance create: %+v", got)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(cassette, "*.json"))
+	if len(files) == 0 {
+		t.Fatal("nothing recorded")
+	}
+	for _, file := range files {
+		data, _ := os.ReadFile(file)
+		for _, secret := range []string{"s3cret-root", "fake-password"} {
+			if strings.Contains(string(data), secret) {
+				t.Errorf("%s recorded in %s:\n%s", secret, file, data)
+			}
+		}
+	}
+}
+
+// TestExportRoundTrip checks that the manifest of an export describes the
+// account it was exported from.
+func TestExportRoundTrip(t *testing.T) {
+	newTestServer(t)
+	if got := runCommand(t, "", "apply", "-f", "testdata/env.yaml"); got.code != 0 {
+		t.Fatalf("apply: %+v", got)
+	}
+
+	exported := runCommand(t, "", "export")
+	if exported.code != 0 {
+		t.Fatalf("export: %+v", exported)
+	}
+	if strings.Contains(exported.stdout, "s3cret") {
+		t.Errorf("export includes the root password:\n%s", exported.stdout)
+	}
+	if got := runCommand(t, exported.stdout, "diff", "-f", "-"); got.code != 0 {
+		t.Errorf("diff of the export: %+v", got)
+	}
+	if again := runCommand(t, "", "export"); again.stdout != exported.stdout {
+		t.Errorf("second export differs:\n%s\nfirst:\n%s", again.stdout, exported.stdout)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Latency = 10 * time.Second
+
+	start := time.Now()
+	got := runCommand(t, "", "instance", "list", "--timeout", "100ms")
+	if got.code != helper.ExitTimeout || got.stderr != "Error: timed out after 100ms\n" {
+		t.Errorf("instance list --timeout 100ms: %+v", got)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("request was not cancelled, the command took %s", elapsed)
+	}
+}
+
+func TestInterrupt(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Latency = 10 * time.Second
+
+	done := make(chan result)
+	go func() { done <- runCommand(t, "", "instance", "list") }()
+	for len(srv.Requests()) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	syscall.Kill(os.Getpid(), syscall.SIGINT)
+
+	select {
+	case got := <-done:
+		if got.code != helper.ExitCanceled || got.stderr != "Error: interrupted\n" {
+			t.Errorf("instance list interrupted: %+v", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("SIGINT did not cancel the request")
+	}
+}
+
+func TestWait(t *testing.T) {
+	t.Run("ready", func(t *testing.T) {
+		srv := newTestServer(t)
+		srv.ProvisionReads = 2
+		runScenario(t, srv, "wait", []step{
+			{args: "instance create web --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045 --wait --poll-interval 1ms"},
+			{args: "kubernetes create prod --dcslug innoida --wait --poll-interval 1ms"},
+			{args: "loadbalancer create lb --dcslug innoida --wait --poll-interval 1ms"},
+			{args: "vpc create private --dcslug innoida --planid 1008 --network 10.210.100.0 --size 24 --wait --poll-interval 1ms"},
+			{args: "autoscaling create web --dcslug innoida --planid 10045 --minsize 1 --maxsize 3 --desiredsize 1 --stackimage ubuntu-22.04-x86_64 --wait --poll-interval 1ms"},
+			{args: "instance delete 1001 --wait --poll-interval 1ms", stdin: "y\n"},
+			{args: "kubernetes delete 1003 --wait --poll-interval 1ms", stdin: "y\n"},
+			{args: "loadbalancer delete 1005 --wait --poll-interval 1ms", stdin: "y\n"},
+			{args: "vpc delete 1007 --wait --poll-interval 1ms", stdin: "y\n"},
+			{args: "autoscaling delete 1009 web --wait --poll-interval 1ms", stdin: "y\n"},
+		})
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		srv := newTestServer(t)
+		srv.ProvisionReads = 2
+		srv.ProvisionStatus = "Error"
+		runScenario(t, srv, "wait-failures", []step{
+			{args: "instance create web --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045 --wait --poll-interval 1ms"},
+			{args: "instance create db --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045 --wait --poll-interval 1h --wait-timeout 50ms"},
+			{args: "instance create db --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045 --wait --poll-interval 0s"},
+		})
+	})
+}
+
+func TestActionWaitWatch(t *testing.T) {
+	t.Run("wait", func(t *testing.T) {
+		srv := newTestServer(t)
+		srv.ActionReads = 3
+		runScenario(t, srv, "action-wait", []step{
+			{args: "instance create web --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045 --show-action"},
+			{args: "action wait 1002 --poll-interval 1ms"},
+			{args: "action wait 1002 --poll-interval 1ms -o json"},
+			{args: "firewall create web --show-action"},
+			{args: "action wait 1004 --poll-interval 1h --wait-timeout 50ms"},
+		})
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		srv := newTestServer(t)
+		srv.ActionReads = 2
+		srv.ActionStatus = "Failed"
+		runScenario(t, srv, "action-wait-failure", []step{
+			{args: "instance create web --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045"},
+			{args: "action wait 1002 --poll-interval 1ms"},
+		})
+	})
+
+	t.Run("watch", func(t *testing.T) {
+		srv := newTestServer(t)
+		srv.ActionReads = 3
+		runScenario(t, srv, "action-watch", []step{
+			{args: "firewall create web"},
+			{args: "instance create web --dcslug innoida --image ubuntu-22.04-x86_64 --planid 10045"},
+			{args: "action watch --resource-type cloud --poll-interval 1ms --timeout 200ms"},
+			{args: "action watch --state completed --poll-interval 1ms --timeout 200ms -o json"},
+		})
+	})
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/uthoplatforms/utho-cli/helper"
+)
+
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Use this command to manage contexts, each holding the token and defaults of one Utho account.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+type contextRow struct {
+	Current bool   `json:"current"`
+	Name    string `json:"name"`
+}
+
+var listContextCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List contexts",
+	Example: "uthoctl context list",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := helper.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		active := cfg.ContextName()
+		contexts := []contextRow{}
+		for _, name := range cfg.ContextNames() {
+			contexts = append(contexts, contextRow{Current: name == active, Name: name})
+		}
+
+		return printResult(cmd, contexts, "Current", "Name")
+	},
+}
+
+var useContextCmd = &cobra.Command{
+	Use:     "use",
+	Short:   "Switch the current context",
+	Example: "uthoctl context use <context-name>",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadContextConfig(args[0])
+		if err != nil {
+			return err
+		}
+		cfg.CurrentContext = args[0]
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Switched to context %q.\n", args[0])
+		return nil
+	},
+}
+
+var renameContextCmd = &cobra.Command{
+	Use:     "rename",
+	Short:   "Rename a context",
+	Example: "uthoctl context rename <context-name> <new-name>",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadContextConfig(args[0])
+		if err != nil {
+			return err
+		}
+		if err := cfg.RenameContext(args[0], args[1]); err != nil {
+			return err
+		}
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Context %q renamed to %q.\n", args[0], args[1])
+		return nil
+	},
+}
+
+var deleteContextCmd = &cobra.Command{
+	Use:     "delete",
+	Short:   "delete a context and its token.",
+	Example: "uthoctl context delete <context-name>",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadContextConfig(args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := helper.Confirm(); err != nil {
+			return err
+		}
+
+		if err := cfg.DeleteContext(args[0]); err != nil {
+			return err
+		}
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Context %q deleted.\n", args[0])
+		return nil
+	},
+}
+
+var setContextCmd = &cobra.Command{
+	Use:     "set",
+	Short:   "Set a setting (api_url, retries, retry_mutating, confirm) or a command flag default for the active context",
+	Example: "uthoctl context set api_url https://api.utho.com/v2/\nuthoctl context set confirm typed --context prod\nuthoctl context set dcslug innoida\nuthoctl context set output json --context prod",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := helper.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		if !contains(helper.ContextSettings, args[0]) {
+			if err := checkContextDefault(cmd.Root(), args[0]); err != nil {
+				return helper.UsageError(err)
+			}
+		}
+
+		ctx := cfg.Context()
+		if ctx == nil {
+			ctx = &helper.Context{}
+			cfg.SetContext(cfg.ContextName(), ctx)
+		}
+		if err := ctx.Set(args[0], args[1]); err != nil {
+			return err
+		}
+		return cfg.Save()
+	},
+}
+
+var unsetContextCmd = &cobra.Command{
+	Use:     "unset",
+	Short:   "Remove a setting or a flag default from the active context",
+	Example: "uthoctl context unset dcslug",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := helper.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		if ctx := cfg.Context(); ctx != nil {
+			if err := ctx.Set(args[0], ""); err != nil {
+				return err
+			}
+			return cfg.Save()
+		}
+		return nil
+	},
+}
+
+// loadContextConfig loads the configuration and checks that the named
+// context exists.
+func loadContextConfig(name string) (*helper.Config, error) {
+	cfg, err := helper.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := cfg.Contexts[name]; !ok {
+		return nil, helper.NotFoundError(fmt.Errorf("context %q not found", name))
+	}
+	return cfg, nil
+}
+
+// globalDefaults are the global flags a context can set a default for. The
+// others would skip confirmations, keep a token outside of the credential
+// store or change which account or file a command uses.
+var globalDefaults = []string{"output", "no-headers", "timeout"}
+
+// unsafeDefaults are the command flags a context cannot set a default for:
+// secrets, and those turning a command on one resource into a bulk one.
+var unsafeDefaults = []string{"root_password", "token-stdin", "store", "helper", "selector", "filter"}
+
+// checkContextDefault checks that the flag name can be given a default in a
+// context: a flag of some command under root other than unsafeDefaults, or
+// one of globalDefaults.
+func checkContextDefault(root *cobra.Command, name string) error {
+	if contains(unsafeDefaults, name) || root.PersistentFlags().Lookup(name) != nil && !contains(globalDefaults, name) {
+		return fmt.Errorf("--%s cannot be set as a default of a context", name)
+	}
+	if contains(globalDefaults, name) || commandFlag(root, name) {
+		return nil
+	}
+	return fmt.Errorf("unknown setting or flag %q, use %s or the flag of a command, eg: dcslug", name, strings.Join(helper.ContextSettings, ", "))
+}
+
+// commandFlag reports whether cmd or one of its subcommands defines the
+// flag name.
+func commandFlag(cmd *cobra.Command, name string) bool {
+	if cmd.LocalNonPersistentFlags().Lookup(name) != nil {
+		return true
+	}
+	for _, sub := range cmd.Commands() {
+		if commandFlag(sub, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyContextDefaults sets the flags listed in the active context defaults
+// that were not given on the command line. Defaults context set refuses,
+// written in the config file by hand or by older versions, are ignored with
+// a warning.
+func applyContextDefaults(cmd *cobra.Command) error {
+	cfg, err := helper.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	ctx := cfg.Context()
+	if ctx == nil {
+		return nil
+	}
+	for name, value := range ctx.Defaults {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		if err := checkContextDefault(cmd.Root(), name); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: ignoring the default of context %q: %v\n", cfg.ContextName(), err)
+			continue
+		}
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return fmt.Errorf("invalid default %s in context %q: %v", name, cfg.ContextName(), err)
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(contextCmd)
+	contextCmd.AddCommand(listContextCmd)
+	contextCmd.AddCommand(useContextCmd)
+	contextCmd.AddCommand(renameContextCmd)
+	contextCmd.AddCommand(deleteContextCmd)
+	contextCmd.AddCommand(setContextCmd)
+	contextCmd.AddCommand(unsetContextCmd)
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/uthoplatforms/utho-cli/helper"
+	"github.com/uthoplatforms/utho-cli/manifest"
+	"github.com/uthoplatforms/utho-cli/printer"
+	"golang.org/x/term"
+)
+
+// diffSymbols and diffColors mark the changes printed by diff.
+var (
+	diffSymbols = map[string]string{
+		manifest.Create:    "+",
+		manifest.Drifted:   "~",
+		manifest.Extra:     "-",
+		manifest.Unchanged: " ",
+	}
+	diffColors = map[string]string{
+		manifest.Create:  "\033[32m",
+		manifest.Drifted: "\033[33m",
+		manifest.Extra:   "\033[31m",
+	}
+)
+
+const colorReset = "\033[0m"
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show how the account differs from a manifest",
+	Long: `Show how the account differs from a manifest: the resources apply would
+create, those that exist with fields that differ from the manifest, and
+the resources of the account that belong to a resource of the manifest
+without being described by it, such as firewall rules or DNS records added
+out of band.
+
+The exit code is 0 when the account matches the manifest and 8 when it
+does not.`,
+	Example: "uthoctl diff -f env.yaml\nuthoctl diff -f env.yaml -o json",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := readManifest(cmd)
+		if err != nil {
+			return err
+		}
+		color, _ := cmd.Flags().GetString("color")
+		colored, err := useColor(cmd.OutOrStdout(), color)
+		if err != nil {
+			return err
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		changes, err := manifest.Diff(client, m)
+		if err != nil {
+			return err
+		}
+
+		if format, _ := cmd.Flags().GetString("output"); strings.EqualFold(strings.TrimSpace(format), printer.FormatTable) {
+			printDiff(cmd.OutOrStdout(), changes, colored)
+		} else if err := printResult(cmd, changes, changeColumns...); err != nil {
+			return err
+		}
+		if manifest.Pending(changes) {
+			return helper.ErrChangesPending
+		}
+		return nil
+	},
+}
+
+// useColor reports whether diff colors its output on out for the --color
+// value: always, never or auto, for a terminal unless NO_COLOR is set.
+func useColor(out io.Writer, color string) (bool, error) {
+	switch color {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		f, ok := out.(*os.File)
+		return ok && term.IsTerminal(int(f.Fd())) && os.Getenv("NO_COLOR") == "", nil
+	}
+	return false, helper.UsageError(fmt.Errorf("invalid --color %q, use auto, always or never", color))
+}
+
+// printDiff prints changes one per line, preceded by their symbol and
+// followed by the fields of the resources to create or extra and the
+// drifted fields, then counts them.
+func printDiff(w io.Writer, changes []*manifest.Change, colored bool) {
+	counts := map[string]int{}
+	for _, c := range changes {
+		counts[c.Action]++
+		start, end := "", ""
+		if code, ok := diffColors[c.Action]; ok && colored {
+			start, end = code, colorReset
+		}
+
+		what := c.Kind + "/" + c.Name
+		if c.Name == "" {
+			what = c.Kind
+		}
+		if c.ID != "" && c.ID != c.Name {
+			what += " " + c.ID
+		}
+		fmt.Fprintf(w, "%s%s %-9s %s%s\n", start, diffSymbols[c.Action], c.Action, what, end)
+
+		switch c.Action {
+		case manifest.Create, manifest.Extra:
+			fields := make([]string, 0, len(c.Fields))
+			for field, value := range c.Fields {
+				if value != "" {
+					fields = append(fields, field)
+				}
+			}
+			sort.Strings(fields)
+			for _, field := range fields {
+				fmt.Fprintf(w, "%s      %s: %s%s\n", start, field, c.Fields[field], end)
+			}
+		case manifest.Drifted:
+			for _, d := range c.Diffs {
+				fmt.Fprintf(w, "%s      %s: %q in the account, %q in the manifest%s\n", start, d.Field, d.Live, d.Manifest, end)
+			}
+		}
+	}
+	fmt.Fprintf(w, "\n%d to create, %d drifted, %d extra, %d unchanged\n",
+		counts[manifest.Create], counts[manifest.Drifted], counts[manifest.Extra], counts[manifest.Unchanged])
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringP("filename", "f", "", "Manifest file, - for stdin")
+	diffCmd.Flags().String("color", "auto", "Color the changes: auto, always or never")
+	diffCmd.MarkFlagRequired("filename")
+}
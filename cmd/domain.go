@@ -1,10 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-
-	"github.com/rodaine/table"
 	"github.com/spf13/cobra"
 	"github.com/uthoplatforms/utho-cli/helper"
 	"github.com/uthoplatforms/utho-go/utho"
@@ -13,8 +9,8 @@ import (
 var domainCmd = &cobra.Command{
 	Use:   "domain",
 	Short: "Use this command to manage domains you have purchased from a domain name registrar that you are managing through the Utho DNS interface.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -22,21 +18,23 @@ var createDomainCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Adds a domain to your account.",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := utho.CreateDomainParams{Domain: args[0]}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
-		domain, err := client.Domain().CreateDomain(utho.CreateDomainParams{Domain: args[0]})
+
+		domain, err := client.Domain().CreateDomain(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Domain", "Status")
-		tbl.AddRow(args[0], domain.Status)
-		tbl.Print()
+		return printResult(cmd, domain, "Status")
 	},
 }
 
@@ -44,45 +42,35 @@ var getDomainCmd = &cobra.Command{
 	Use:   "get",
 	Short: "Get domain info",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		domain, err := client.Domain().ReadDomain(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Domain", "Dns Record Count", "Created At")
-		tbl.AddRow(domain.Domain, domain.DnsrecordCount, domain.CreatedAt)
-		tbl.Print()
+		return printResult(cmd, domain, "Domain", "DnsrecordCount", "CreatedAt")
 	},
 }
 
 var listDomainCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List domain info",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		domains, err := client.Domain().ListDomains()
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Domain", "Dns Record Count", "Created At")
-		for _, domain := range domains {
-			tbl.AddRow(domain.Domain, domain.DnsrecordCount, domain.CreatedAt)
-		}
-		tbl.Print()
+		return printResult(cmd, domains, "Domain", "DnsrecordCount", "CreatedAt")
 	},
 }
 
@@ -90,26 +78,26 @@ var deleteDomainCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "delete a domain from your account.",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{Name: args[0]})
 		}
 
-		client, err := helper.NewUthoClient()
+		if err := helper.Confirm(); err != nil {
+			return err
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		domain, err := client.Domain().DeleteDomain(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + domain.Status)
+		return printResult(cmd, domain, "Status")
 	},
 }
 
@@ -117,8 +105,8 @@ var dnsCmd = &cobra.Command{
 	Use:   "records",
 	Short: "Use this command to to manage the DNS records for your domains.",
 
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -126,12 +114,7 @@ var createDomainRecordCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Adds a record to your domain.",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
+	RunE: func(cmd *cobra.Command, args []string) error {
 		recordType, _ := cmd.Flags().GetString("type")
 		hostname, _ := cmd.Flags().GetString("hostname")
 		value, _ := cmd.Flags().GetString("value")
@@ -152,15 +135,21 @@ var createDomainRecordCmd = &cobra.Command{
 			Priority: priority,
 			Wight:    wight,
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		record, err := client.Domain().CreateDnsRecord(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Domain", "Record Id", "Record Name", "Status")
-		tbl.AddRow(args[0], record.ID, hostname, record.Status)
-		tbl.Print()
+		return printResult(cmd, record, "ID", "Status")
 	},
 }
 
@@ -169,23 +158,17 @@ var listDomainRecordCmd = &cobra.Command{
 	Short:   "List domain Record",
 	Example: "uthoctl domain records list <domain>",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		dnsRecords, err := client.Domain().ListDnsRecords(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("id", "hostname", "type", "value", "ttl", "priority")
-		for _, record := range dnsRecords {
-			tbl.AddRow(record.ID, record.Hostname, record.Type, record.Value, record.TTL, record.Priority)
-		}
-		tbl.Print()
+		return printResult(cmd, dnsRecords, "ID", "Hostname", "Type", "Value", "TTL", "Priority")
 	},
 }
 
@@ -194,26 +177,26 @@ var deleteDomainRecordCmd = &cobra.Command{
 	Short:   "delete a domain record from your account.",
 	Example: "uthoctl domain records delete <domain> <record-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ParentID: args[0], ID: args[1]})
+		}
+
+		if err := helper.Confirm(); err != nil {
+			return err
 		}
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		record, err := client.Domain().DeleteDnsRecord(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + record.Status)
+		return printResult(cmd, record, "Status")
 	},
 }
 
@@ -234,6 +217,9 @@ func init() {
 	createDomainRecordCmd.Flags().String("port", "", "Port")
 	createDomainRecordCmd.Flags().String("priority", "", "The priority of the host (for SRV and MX records. null otherwise). ")
 	createDomainRecordCmd.Flags().String("wight", "", "The weight of records with the same priority (for SRV records only. null otherwise).")
+	createDomainRecordCmd.MarkFlagRequired("type")
+	createDomainRecordCmd.MarkFlagRequired("hostname")
+	createDomainRecordCmd.MarkFlagRequired("value")
 
 	dnsCmd.AddCommand(listDomainRecordCmd)
 	dnsCmd.AddCommand(deleteDomainRecordCmd)
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/uthoplatforms/utho-cli/helper"
+	"github.com/uthoplatforms/utho-cli/printer"
+)
+
+// dryRunRequest is what --dry-run prints for the requests that only take
+// IDs, such as most deletes, where there is no utho params struct to show.
+type dryRunRequest struct {
+	Command  string `json:"command"`
+	ParentID string `json:"parent_id,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Dcslug   string `json:"dcslug,omitempty"`
+}
+
+// dryRun reports whether --dry-run is set. Mutating commands then check
+// their flags, including the required ones, and build their request as
+// usual, but print it with printDryRun instead of sending it.
+func dryRun() bool {
+	return viper.GetBool("dry_run")
+}
+
+// printDryRun prints params, the request cmd would send, with all its
+// fields as table columns and its secrets, such as root_password, redacted.
+// A dryRunRequest gets the command path filled in.
+func printDryRun(cmd *cobra.Command, params any) error {
+	if req, ok := params.(dryRunRequest); ok {
+		req.Command = commandName(cmd)
+		params = req
+	}
+	params = helper.RedactFields(params)
+	return printResult(cmd, params, printer.NestedFields(params)...)
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/uthoplatforms/utho-cli/helper"
+	"github.com/uthoplatforms/utho-cli/manifest"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Print a manifest of the resources of the account",
+	Long: `Print a manifest of the resources of the account, which apply and diff
+read: instances, VPCs, firewalls with their rules, domains with their
+records, load balancers with their frontends, ACLs, backends and routes,
+target groups with their targets, Kubernetes clusters and auto scaling
+groups with their policies and schedules.
+
+Resources are ordered by kind, then by name, so that exports of the same
+account compare cleanly in version control. Those the API does not name,
+such as DNS records, are named after their fields. Secrets, such as root
+passwords, are never exported.
+
+With --format terraform, the instances, VPCs, firewalls, load balancers,
+domains and DNS records are written as resources of the Utho Terraform
+provider instead, each followed by an import block so that Terraform 1.5
+or later adopts the existing resource on its first plan rather than
+creating another. The API does not return some attributes the provider
+requires, such as the plan of instances and VPCs or the location of load
+balancers: they are written as "TODO" with a comment, and listed in a
+warning, to be replaced with the values the resources were created with
+before running terraform plan.`,
+	Example: "uthoctl export > env.yaml\nuthoctl export --kind domain,loadbalancer\nuthoctl export --location innoida\nuthoctl export --format terraform > main.tf",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kinds, _ := cmd.Flags().GetStringSlice("kind")
+		location, _ := cmd.Flags().GetString("location")
+		format, _ := cmd.Flags().GetString("format")
+		switch format {
+		case "yaml":
+		case "terraform":
+			supported := map[string]bool{}
+			for _, kind := range manifest.TerraformKinds() {
+				supported[kind] = true
+			}
+			if len(kinds) == 0 {
+				kinds = manifest.TerraformKinds()
+			}
+			for _, kind := range kinds {
+				if !supported[kind] {
+					return helper.UsageError(fmt.Errorf("kind %q has no Terraform resource, use %s", kind, strings.Join(manifest.TerraformKinds(), ", ")))
+				}
+			}
+		default:
+			return helper.UsageError(fmt.Errorf("invalid --format %q, use yaml or terraform", format))
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		m, err := manifest.Export(client, manifest.ExportOptions{Kinds: kinds, Dcslug: location})
+		if err != nil {
+			return err
+		}
+		var data []byte
+		var todo []string
+		if format == "terraform" {
+			data, todo, err = m.MarshalTerraform()
+		} else {
+			data, err = m.Marshal()
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := cmd.OutOrStdout().Write(data); err != nil {
+			return err
+		}
+		if len(todo) > 0 {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: the API does not return %s, replace their TODO placeholders before running terraform plan\n", strings.Join(todo, ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringSlice("kind", nil, "Comma separated kinds of resources to export, eg: instance,firewall")
+	exportCmd.Flags().String("location", "", "Only export the resources of a location, eg: innoida")
+	exportCmd.Flags().String("format", "yaml", "Output format: yaml, a manifest, or terraform")
+}
@@ -1,10 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-
-	"github.com/rodaine/table"
 	"github.com/spf13/cobra"
 	"github.com/uthoplatforms/utho-cli/helper"
 	"github.com/uthoplatforms/utho-go/utho"
@@ -13,8 +9,8 @@ import (
 var firewallCmd = &cobra.Command{
 	Use:   "firewall",
 	Short: "Use this command to manage firewalls.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -23,25 +19,25 @@ var createFirewallCmd = &cobra.Command{
 	Short:   "Create a firewall.",
 	Example: "uthoctl firewall create <firewall-name>",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
+	RunE: func(cmd *cobra.Command, args []string) error {
 		params := utho.CreateFirewallParams{
 			Name: args[0],
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		firewall, err := client.Firewall().Create(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Firewall Name", "Firewall Id", "Status")
-		tbl.AddRow(args[0], firewall.ID, firewall.Status)
-		tbl.Print()
+		return printResult(cmd, firewall, "ID", "Status")
 	},
 }
 
@@ -50,21 +46,17 @@ var getFirewallCmd = &cobra.Command{
 	Short:   "Get firewall info",
 	Example: "uthoctl firewall get <firewall-id>",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		firewall, err := client.Firewall().Read(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name", "CreatedAt", "Rulecount", "Serverscount")
-		tbl.AddRow(firewall.ID, firewall.Name, firewall.CreatedAt, firewall.Rulecount, firewall.Serverscount)
-		tbl.Print()
+		return printResult(cmd, firewall, "ID", "Name", "CreatedAt", "Rulecount", "Serverscount")
 	},
 }
 
@@ -72,52 +64,46 @@ var listFirewallCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List firewall info",
 	Example: "uthoctl firewall list",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		firewalls, err := client.Firewall().List()
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name", "CreatedAt", "Rulecount", "Serverscount")
-		for _, firewall := range firewalls {
-			tbl.AddRow(firewall.ID, firewall.Name, firewall.CreatedAt, firewall.Rulecount, firewall.Serverscount)
-		}
-		tbl.Print()
+		return printResult(cmd, firewalls, "ID", "Name", "CreatedAt", "Rulecount", "Serverscount")
 	},
 }
 
 var deleteFirewallCmd = &cobra.Command{
 	Use:     "delete",
 	Short:   "delete a firewall from your account.",
-	Example: "uthoctl firewall delete <firewall-id>",
-	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	Example: "uthoctl firewall delete <firewall-id>\nuthoctl firewall delete --selector 'name=test-*,created-before=720h'",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ID: args[0]})
+		}
+
+		if err := helper.Confirm(); err != nil {
+			return err
 		}
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		firewall, err := client.Firewall().Delete(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + firewall.Status)
+		return printResult(cmd, firewall, "Status")
 	},
 }
 
@@ -125,8 +111,8 @@ var deleteFirewallCmd = &cobra.Command{
 var firewallruleCmd = &cobra.Command{
 	Use:   "firewallrule",
 	Short: "Use this command to manage firewall rules.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -135,13 +121,7 @@ var createFirewallruleCmd = &cobra.Command{
 	Short:   "Create a firewall rule.",
 	Example: "uthoctl firewall firewallrule create <firewall-id>",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
+	RunE: func(cmd *cobra.Command, args []string) error {
 		firewallRuleType, _ := cmd.Flags().GetString("type")
 		service, _ := cmd.Flags().GetString("service")
 		protocol, _ := cmd.Flags().GetString("protocol")
@@ -155,38 +135,40 @@ var createFirewallruleCmd = &cobra.Command{
 			Port:       port,
 			Addresses:  addresses,
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		firewallrule, err := client.Firewall().CreateFirewallRule(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Firewallrule Id", "Status")
-		tbl.AddRow(firewallrule.ID, firewallrule.Status)
-		tbl.Print()
+		return printResult(cmd, firewallrule, "ID", "Status")
 	},
 }
 
 var getFirewallruleCmd = &cobra.Command{
 	Use:     "get",
 	Short:   "Get firewall firewallrule info",
-	Example: "uthoctl firewall firewallrule get <firewall-id> <firewallrule-id",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	Example: "uthoctl firewall firewallrule get <firewall-id> <firewallrule-id>",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		firewallrule, err := client.Firewall().ReadFirewallRule(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Firewallid", "Type", "Service", "Protocol", "Port", "Addresses")
-		tbl.AddRow(firewallrule.ID, firewallrule.Firewallid, firewallrule.Type, firewallrule.Service, firewallrule.Protocol, firewallrule.Port, firewallrule.Addresses)
-		tbl.Print()
+		return printResult(cmd, firewallrule, "ID", "Firewallid", "Type", "Service", "Protocol", "Port", "Addresses")
 	},
 }
 
@@ -194,24 +176,19 @@ var listFirewallruleCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List firewall Firewallrule",
 	Example: "uthoctl firewall firewallrule list <firewall-id>",
-	Run: func(cmd *cobra.Command, args []string) {
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		firewallrules, err := client.Firewall().ListFirewallRules(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Firewallid", "Type", "Service", "Protocol", "Port", "Addresses")
-		for _, firewallrule := range firewallrules {
-			tbl.AddRow(firewallrule.ID, firewallrule.Firewallid, firewallrule.Type, firewallrule.Service, firewallrule.Protocol, firewallrule.Port, firewallrule.Addresses)
-		}
-		tbl.Print()
+		return printResult(cmd, firewallrules, "ID", "Firewallid", "Type", "Service", "Protocol", "Port", "Addresses")
 	},
 }
 
@@ -220,26 +197,26 @@ var deleteFirewallruleCmd = &cobra.Command{
 	Short:   "delete a firewall policy from your account.",
 	Example: "uthoctl firewall firewallrule delete <firewall-id> <firewallrule-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ParentID: args[0], ID: args[1]})
+		}
+
+		if err := helper.Confirm(); err != nil {
+			return err
 		}
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		firewallrule, err := client.Firewall().DeleteFirewallRule(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + firewallrule.Status)
+		return printResult(cmd, firewallrule, "Status")
 	},
 }
 
@@ -248,19 +225,33 @@ func init() {
 	// Firewall
 	firewallCmd.AddCommand(createFirewallCmd)
 	firewallCmd.AddCommand(getFirewallCmd)
+	resolveNames(getFirewallCmd, helper.KindFirewall)
 	firewallCmd.AddCommand(listFirewallCmd)
 	firewallCmd.AddCommand(deleteFirewallCmd)
+	resolveNames(deleteFirewallCmd, helper.KindFirewall)
+	addSelector(deleteFirewallCmd, firewallSelection, "delete", func(client utho.Client, c candidate) error {
+		_, err := client.Firewall().Delete(c.ID)
+		return err
+	})
 
 	// Firewall Rule
 	firewallCmd.AddCommand(firewallruleCmd)
 	firewallruleCmd.AddCommand(createFirewallruleCmd)
+	resolveNames(createFirewallruleCmd, helper.KindFirewall)
 	createFirewallruleCmd.Flags().String("type", "", "Incoming or outgoing traffic eg: incoming, outgonig")
 	createFirewallruleCmd.Flags().String("service", "", "")
 	createFirewallruleCmd.Flags().String("protocol", "", "The type of traffic to be allowed. This may be one of 'tcp', 'udp', or 'icmp'")
 	createFirewallruleCmd.Flags().String("port", "", "The ports on which traffic will be allowed specified as a string containing a single port, a range (e.g. '8000-9000'), or 'ALL' to open all ports for a protocol. ")
 	createFirewallruleCmd.Flags().String("addresses", "", "An array of strings containing the IPv4 addresses, IPv6 addresses, IPv4 CIDRs, and/or IPv6 CIDRs to which the Firewall will allow traffic")
+	createFirewallruleCmd.MarkFlagRequired("type")
+	createFirewallruleCmd.MarkFlagRequired("protocol")
+	createFirewallruleCmd.MarkFlagRequired("port")
+	createFirewallruleCmd.MarkFlagRequired("addresses")
 
 	firewallruleCmd.AddCommand(getFirewallruleCmd)
+	resolveNames(getFirewallruleCmd, helper.KindFirewall)
 	firewallruleCmd.AddCommand(listFirewallruleCmd)
+	resolveNames(listFirewallruleCmd, helper.KindFirewall)
 	firewallruleCmd.AddCommand(deleteFirewallruleCmd)
+	resolveNames(deleteFirewallruleCmd, helper.KindFirewall)
 }
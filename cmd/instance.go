@@ -1,10 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-
-	"github.com/rodaine/table"
 	"github.com/spf13/cobra"
 	"github.com/uthoplatforms/utho-cli/helper"
 	"github.com/uthoplatforms/utho-go/utho"
@@ -13,8 +9,8 @@ import (
 var instanceCmd = &cobra.Command{
 	Use:   "instance",
 	Short: "Use this command to manage compute instances.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -22,12 +18,7 @@ var createCloudInstanceCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a compute instance.",
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
+	RunE: func(cmd *cobra.Command, args []string) error {
 		dcslug, _ := cmd.Flags().GetString("dcslug")
 		image, _ := cmd.Flags().GetString("image")
 		planid, _ := cmd.Flags().GetString("planid")
@@ -57,119 +48,160 @@ var createCloudInstanceCmd = &cobra.Command{
 			Sshkeys:      sshkeys,
 			Cloud:        []utho.CloudHostname{{Hostname: args[0]}},
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		instance, err := client.CloudInstances().Create(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Instance Name", "ID", "Password", "Ipv4", "Status")
-		tbl.AddRow(args[0], instance.ID, instance.Password, instance.Ipv4, instance.Status)
-		tbl.Print()
+		created := createdInstance{
+			Hostname: args[0],
+			ID:       instance.ID,
+			Password: instance.Password,
+			Ipv4:     instance.Ipv4,
+			Status:   instance.Status,
+			Message:  instance.Message,
+		}
+		if err := printResult(cmd, created, "Hostname", "ID", "Password", "Ipv4", "Status"); err != nil {
+			return err
+		}
+		return waitFor(cmd, "instance "+instance.ID, waitReady, instanceStatus(instance.ID))
 	},
 }
 
+// createdInstance is the response of instance create with the name the
+// instance was created with, which the API does not return.
+type createdInstance struct {
+	Hostname string `json:"hostname"`
+	ID       string `json:"cloudid"`
+	Password string `json:"password"`
+	Ipv4     string `json:"ipv4"`
+	Status   string `json:"status"`
+	Message  string `json:"message"`
+}
+
 var getCloudInstanceCmd = &cobra.Command{
 	Use:     "get",
 	Short:   "Get instance info",
 	Example: "uthoctl instance get <instance-id>",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		instance, err := client.CloudInstances().Read(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Hostname", "CPU", "RAM", "Disksize", "IP", "Billingcycle", "Image")
-		tbl.AddRow(instance.ID, instance.Hostname, instance.CPU, instance.RAM, instance.Disksize, instance.IP, instance.Billingcycle, instance.Image.Image)
-		tbl.Print()
+		return printResult(cmd, instance, "ID", "Hostname", "CPU", "RAM", "Disksize", "IP", "Billingcycle", "Image.Image")
 	},
 }
 
 var listCloudInstanceCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List instance info",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		instances, err := client.CloudInstances().List()
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Hostname", "CPU", "RAM", "Disksize", "IP", "Billingcycle", "Image")
-		for _, instance := range instances {
-			tbl.AddRow(instance.ID, instance.Hostname, instance.CPU, instance.RAM, instance.Disksize, instance.IP, instance.Billingcycle, instance.Image.Image)
-		}
-		tbl.Print()
+		return printResult(cmd, instances, "ID", "Hostname", "CPU", "RAM", "Disksize", "IP", "Billingcycle", "Image.Image")
 	},
 }
 
 var deleteCloudInstanceCmd = &cobra.Command{
 	Use:     "delete",
 	Short:   "delete a instance from your account.",
-	Example: "uthoctl instance delete <instance-id>",
+	Example: "uthoctl instance delete <instance-id>\nuthoctl instance delete --selector 'name=test-*,status=stopped'",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ID: args[0]})
 		}
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
+		}
+		if err := helper.ConfirmDelete("instance", args[0], func() string {
+			instance, err := client.CloudInstances().Read(args[0])
+			if err != nil {
+				return ""
+			}
+			return instance.Hostname
+		}); err != nil {
+			return err
 		}
 
 		instance, err := client.CloudInstances().Delete(args[0],
 			utho.DeleteCloudInstanceParams{Confirm: "I am aware this action will delete data and server permanently"},
 		)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + instance.Status)
+		if err := printResult(cmd, instance, "Status"); err != nil {
+			return err
+		}
+		return waitFor(cmd, "instance "+args[0], waitGone, instanceStatus(args[0]))
 	},
 }
 
+// instanceStatus reads the status of the instance id, for --wait.
+func instanceStatus(id string) statusReader {
+	return func(client utho.Client) (string, error) {
+		instance, err := client.CloudInstances().Read(id)
+		if err != nil {
+			return "", err
+		}
+		return instance.Status, nil
+	}
+}
+
 var snapshotCmd = &cobra.Command{
 	Use:   "snapshot",
 	Short: "Use this command to to manage snapshot for your instances.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
 var createSnapshotCmd = &cobra.Command{
-	Use:   "create",
-	Short: "Create a snapshot for compute instance.",
-	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	Use:     "create",
+	Short:   "Create a snapshot for compute instance.",
+	Example: "uthoctl instance snapshot create <instance-id>\nuthoctl instance snapshot create --selector dcslug=innoida",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ID: args[0]})
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
+
 		instance, err := client.CloudInstances().CreateSnapshot(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + instance.Status)
+		return printResult(cmd, instance, "Status")
 	},
 }
 
@@ -178,83 +210,86 @@ var deleteSnapshotCmd = &cobra.Command{
 	Short:   "delete an instance snapshot.",
 	Example: "uthoctl instance snapshot delete <instance-id> <snapshot-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ParentID: args[0], ID: args[1]})
 		}
 
-		client, err := helper.NewUthoClient()
+		if err := helper.Confirm(); err != nil {
+			return err
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		instance, err := client.CloudInstances().DeleteSnapshot(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + instance.Status)
+		return printResult(cmd, instance, "Status")
 	},
 }
 
 var backupCmd = &cobra.Command{
 	Use:   "backup",
 	Short: "Use this command to to manage backup for your instances.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
 var enableBackupCmd = &cobra.Command{
 	Use:     "enable",
 	Short:   "enable backup for compute instance.",
-	Example: "uthoctl instance backup enable <instance-id>",
+	Example: "uthoctl instance backup enable <instance-id>\nuthoctl instance backup enable --selector 'name=prod-*'",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ID: args[0]})
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
+
 		instance, err := client.CloudInstances().EnableBackup(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + instance.Status)
+		return printResult(cmd, instance, "Status")
 	},
 }
 
 var disableBackupCmd = &cobra.Command{
 	Use:     "disable",
 	Short:   "disable an instance backup.",
-	Example: "uthoctl instance backup disable <instance-id>",
+	Example: "uthoctl instance backup disable <instance-id>\nuthoctl instance backup disable --selector 'name=test-*'",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ID: args[0]})
 		}
 
-		client, err := helper.NewUthoClient()
+		if err := helper.Confirm(); err != nil {
+			return err
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		instance, err := client.CloudInstances().DisableBackup(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + instance.Status)
+		return printResult(cmd, instance, "Status")
 	},
 }
 
@@ -262,6 +297,7 @@ func init() {
 	rootCmd.AddCommand(instanceCmd)
 
 	instanceCmd.AddCommand(createCloudInstanceCmd)
+	addWaitFlags(createCloudInstanceCmd, "instance is active")
 	createCloudInstanceCmd.Flags().String("dcslug", "", "Provide Zone dcslug eg: innoida")
 	createCloudInstanceCmd.Flags().String("image", "", "Image name eg: centos-7.4-x86_64")
 	createCloudInstanceCmd.Flags().String("planid", "", "Cloud Plan ID")
@@ -275,19 +311,48 @@ func init() {
 	createCloudInstanceCmd.Flags().String("backupid", "", "Provide a backupid if you have a backup in same datacenter location")
 	createCloudInstanceCmd.Flags().String("snapshotid", "", "Provide a snapshot id if you have a snapshot in same datacenter location")
 	createCloudInstanceCmd.Flags().String("sshkeys", "", "Privide SSH Key ids or pass multiple SSH Key ids with commans (eg: 432,331)")
+	resolveFlagNames(createCloudInstanceCmd, "firewall", helper.KindFirewall)
+	createCloudInstanceCmd.MarkFlagRequired("dcslug")
+	createCloudInstanceCmd.MarkFlagRequired("image")
+	createCloudInstanceCmd.MarkFlagRequired("planid")
 
 	instanceCmd.AddCommand(getCloudInstanceCmd)
+	resolveNames(getCloudInstanceCmd, helper.KindInstance)
 	instanceCmd.AddCommand(listCloudInstanceCmd)
 	instanceCmd.AddCommand(deleteCloudInstanceCmd)
+	resolveNames(deleteCloudInstanceCmd, helper.KindInstance)
+	addWaitFlags(deleteCloudInstanceCmd, "instance is deleted")
+	addSelector(deleteCloudInstanceCmd, instanceSelection, "delete", func(client utho.Client, c candidate) error {
+		_, err := client.CloudInstances().Delete(c.ID,
+			utho.DeleteCloudInstanceParams{Confirm: "I am aware this action will delete data and server permanently"},
+		)
+		return err
+	})
 
 	// Snapshot
 	instanceCmd.AddCommand(snapshotCmd)
 	snapshotCmd.AddCommand(createSnapshotCmd)
+	resolveNames(createSnapshotCmd, helper.KindInstance)
+	addSelector(createSnapshotCmd, instanceSelection, "snapshot", func(client utho.Client, c candidate) error {
+		_, err := client.CloudInstances().CreateSnapshot(c.ID)
+		return err
+	})
 	snapshotCmd.AddCommand(deleteSnapshotCmd)
+	resolveNames(deleteSnapshotCmd, helper.KindInstance)
 
 	// Backup
 	instanceCmd.AddCommand(backupCmd)
 	backupCmd.AddCommand(enableBackupCmd)
+	resolveNames(enableBackupCmd, helper.KindInstance)
+	addSelector(enableBackupCmd, instanceSelection, "enable the backups of", func(client utho.Client, c candidate) error {
+		_, err := client.CloudInstances().EnableBackup(c.ID)
+		return err
+	})
 	backupCmd.AddCommand(disableBackupCmd)
+	resolveNames(disableBackupCmd, helper.KindInstance)
+	addSelector(disableBackupCmd, instanceSelection, "disable the backups of", func(client utho.Client, c candidate) error {
+		_, err := client.CloudInstances().DisableBackup(c.ID)
+		return err
+	})
 
 }
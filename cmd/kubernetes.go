@@ -1,10 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-
-	"github.com/rodaine/table"
 	"github.com/spf13/cobra"
 	"github.com/uthoplatforms/utho-cli/helper"
 	"github.com/uthoplatforms/utho-go/utho"
@@ -13,8 +9,8 @@ import (
 var kubernetesCmd = &cobra.Command{
 	Use:   "kubernetes",
 	Short: "Use this command to manage kubernetes(k8s) cluster.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -23,37 +19,45 @@ var createKubernetesCmd = &cobra.Command{
 	Short:   "Create a kubernetes.",
 	Example: "uthoctl kubernetes create <kubernetes-name>",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
+	RunE: func(cmd *cobra.Command, args []string) error {
 		dcslug, _ := cmd.Flags().GetString("dcslug")
-		clusterLabel, _ := cmd.Flags().GetString("cluster_label")
 		clusterVersion, _ := cmd.Flags().GetString("cluster_version")
 		auth, _ := cmd.Flags().GetString("auth")
 		vpc, _ := cmd.Flags().GetString("vpc")
 		securityGroups, _ := cmd.Flags().GetString("security_groups")
+		// Earlier versions took the label from --cluster_label only.
+		label := args[0]
+		if cmd.Flags().Changed("cluster_label") {
+			label, _ = cmd.Flags().GetString("cluster_label")
+		}
 
 		params := utho.CreateKubernetesParams{
 			Dcslug:         dcslug,
-			ClusterLabel:   clusterLabel,
+			ClusterLabel:   label,
 			ClusterVersion: clusterVersion,
 			Nodepools:      []utho.CreateNodepoolsParams{},
 			Auth:           auth,
 			Vpc:            vpc,
 			SecurityGroups: securityGroups,
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		kubernetes, err := client.Kubernetes().Create(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Kubernetes Name", "Kubernetes Id", "Status")
-		tbl.AddRow(args[0], kubernetes.ID, kubernetes.Status)
-		tbl.Print()
+		if err := printResult(cmd, kubernetes, "ID", "Status"); err != nil {
+			return err
+		}
+		return waitFor(cmd, "cluster "+kubernetes.ID, waitReady, clusterStatus(kubernetes.ID))
 	},
 }
 
@@ -62,21 +66,17 @@ var getKubernetesCmd = &cobra.Command{
 	Short:   "Get kubernetes info",
 	Example: "uthoctl kubernetes get <kubernetes-id>",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		kubernetes, err := client.Kubernetes().Read(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Dcslug", "Name", "RAM", "CPU", "Disksize", "IP", "Status", "WorkerCount")
-		tbl.AddRow(kubernetes.ID, kubernetes.Dcslug, kubernetes.Hostname, kubernetes.RAM, kubernetes.CPU, kubernetes.Disksize, kubernetes.IP, kubernetes.Status, kubernetes.WorkerCount)
-		tbl.Print()
+		return printResult(cmd, kubernetes, "ID", "Dcslug", "Hostname", "RAM", "CPU", "Disksize", "IP", "Status", "WorkerCount")
 	},
 }
 
@@ -84,64 +84,78 @@ var listKubernetesCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List kubernetes info",
 	Example: "uthoctl kubernetes list",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		kubernetess, err := client.Kubernetes().List()
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Dcslug", "Name", "RAM", "CPU", "Disksize", "IP", "Status", "WorkerCount")
-		for _, kubernetes := range kubernetess {
-			tbl.AddRow(kubernetes.ID, kubernetes.Dcslug, kubernetes.Hostname, kubernetes.RAM, kubernetes.CPU, kubernetes.Disksize, kubernetes.IP, kubernetes.Status, kubernetes.WorkerCount)
-		}
-		tbl.Print()
+		return printResult(cmd, kubernetess, "ID", "Dcslug", "Hostname", "RAM", "CPU", "Disksize", "IP", "Status", "WorkerCount")
 	},
 }
 
 var deleteKubernetesCmd = &cobra.Command{
 	Use:     "delete",
 	Short:   "delete a kubernetes from your account.",
-	Example: "uthoctl kubernetes delete <kubernetes-id>",
+	Example: "uthoctl kubernetes delete <kubernetes-id>\nuthoctl kubernetes delete --selector 'name=test-*'",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := utho.DeleteKubernetesParams{
+			ClusterId: args[0],
+			Confirm:   "I am aware this action will delete data and cluster permanently",
+		}
+		if dryRun() {
+			return printDryRun(cmd, params)
 		}
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
+		}
+		if err := helper.ConfirmDelete("cluster", args[0], func() string {
+			cluster, err := client.Kubernetes().Read(args[0])
+			if err != nil {
+				return ""
+			}
+			return cluster.Hostname
+		}); err != nil {
+			return err
 		}
 
-		kubernetes, err := client.Kubernetes().Delete(utho.DeleteKubernetesParams{
-			ClusterId: args[0],
-			Confirm:   "I am aware this action will delete data and cluster permanently",
-		})
+		kubernetes, err := client.Kubernetes().Delete(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + kubernetes.Status)
+		if err := printResult(cmd, kubernetes, "Status"); err != nil {
+			return err
+		}
+		return waitFor(cmd, "cluster "+args[0], waitGone, clusterStatus(args[0]))
 	},
 }
 
+// clusterStatus reads the status of the cluster id, for --wait.
+func clusterStatus(id string) statusReader {
+	return func(client utho.Client) (string, error) {
+		cluster, err := client.Kubernetes().Read(id)
+		if err != nil {
+			return "", err
+		}
+		return cluster.Status, nil
+	}
+}
+
 // Loadbalancer
 var kubernetesLoadbalancerCmd = &cobra.Command{
 	Use:   "loadbalancer",
 	Short: "Use this command to manage kubernetes Load Balancer.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -150,49 +164,45 @@ var createKubernetesLoadbalancerCmd = &cobra.Command{
 	Short:   "Create a kubernetes loadbalancer.",
 	Example: "uthoctl kubernetes loadbalancer create <kubernetes-id> <loadbalancer-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
+	RunE: func(cmd *cobra.Command, args []string) error {
 		params := utho.CreateKubernetesLoadbalancerParams{
 			KubernetesId:   args[0],
 			LoadbalancerId: args[1],
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		loadbalancer, err := client.Kubernetes().CreateLoadbalancer(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Kubernetes Loadbalancer Id", "Status")
-		tbl.AddRow(loadbalancer.ID, loadbalancer.Status)
-		tbl.Print()
+		return printResult(cmd, loadbalancer, "ID", "Status")
 	},
 }
 
 var getKubernetesLoadbalancerCmd = &cobra.Command{
 	Use:     "get",
 	Short:   "Get kubernetes loadbalancer info",
-	Example: "uthoctl kubernetes loadbalancer get <kubernetes-id> <loadbalancer-id",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	Example: "uthoctl kubernetes loadbalancer get <kubernetes-id> <loadbalancer-id>",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		loadbalancer, err := client.Kubernetes().ReadLoadbalancer(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name", "IP")
-		tbl.AddRow(loadbalancer.ID, loadbalancer.Name, loadbalancer.IP)
-		tbl.Print()
+		return printResult(cmd, loadbalancer, "ID", "Name", "IP")
 	},
 }
 
@@ -200,24 +210,19 @@ var listKubernetesLoadbalancerCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List kubernetes Loadbalancer",
 	Example: "uthoctl kubernetes loadbalancer list <kubernetes-id>",
-	Run: func(cmd *cobra.Command, args []string) {
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		loadbalancers, err := client.Kubernetes().ListLoadbalancers(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name", "IP")
-		for _, loadbalancer := range loadbalancers {
-			tbl.AddRow(loadbalancer.ID, loadbalancer.Name, loadbalancer.IP)
-		}
-		tbl.Print()
+		return printResult(cmd, loadbalancers, "ID", "Name", "IP")
 	},
 }
 
@@ -226,26 +231,26 @@ var deleteKubernetesLoadbalancerCmd = &cobra.Command{
 	Short:   "delete a kubernetes policy from your account.",
 	Example: "uthoctl kubernetes loadbalancer delete <kubernetes-id> <loadbalancer-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ParentID: args[0], ID: args[1]})
+		}
+
+		if err := helper.Confirm(); err != nil {
+			return err
 		}
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		loadbalancer, err := client.Kubernetes().DeleteLoadbalancer(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + loadbalancer.Status)
+		return printResult(cmd, loadbalancer, "Status")
 	},
 }
 
@@ -253,8 +258,8 @@ var deleteKubernetesLoadbalancerCmd = &cobra.Command{
 var kubernetesecuritygroupCmd = &cobra.Command{
 	Use:   "securitygroup",
 	Short: "Use this command to manage kubernetes Security Group.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -263,49 +268,45 @@ var createKubernetesSecuritygroupCmd = &cobra.Command{
 	Short:   "Create a kubernetes securitygroup.",
 	Example: "uthoctl kubernetes securitygroup create <kubernetes-id> <securitygroup-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
+	RunE: func(cmd *cobra.Command, args []string) error {
 		params := utho.CreateKubernetesSecurityGroupParams{
 			KubernetesId:              args[0],
 			KubernetesSecurityGroupId: args[1],
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		securitygroup, err := client.Kubernetes().CreateSecurityGroup(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Securitygroup id", "Status")
-		tbl.AddRow(args[1], securitygroup.Status)
-		tbl.Print()
+		return printResult(cmd, securitygroup, "Status")
 	},
 }
 
 var getKubernetesSecuritygroupCmd = &cobra.Command{
 	Use:     "get",
 	Short:   "Get kubernetes securitygroup info",
-	Example: "uthoctl kubernetes securitygroup get <kubernetes-id> <securitygroup-id",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	Example: "uthoctl kubernetes securitygroup get <kubernetes-id> <securitygroup-id>",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		securitygroup, err := client.Kubernetes().ReadSecurityGroup(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name")
-		tbl.AddRow(securitygroup.ID, securitygroup.Name)
-		tbl.Print()
+		return printResult(cmd, securitygroup, "ID", "Name")
 	},
 }
 
@@ -313,24 +314,19 @@ var listKubernetesSecuritygroupCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List kubernetes policy",
 	Example: "uthoctl kubernetes securitygroup list <kubernetes-id>",
-	Run: func(cmd *cobra.Command, args []string) {
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		securitygroups, err := client.Kubernetes().ListSecurityGroups(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name")
-		for _, securitygroup := range securitygroups {
-			tbl.AddRow(securitygroup.ID, securitygroup.Name)
-		}
-		tbl.Print()
+		return printResult(cmd, securitygroups, "ID", "Name")
 	},
 }
 
@@ -339,26 +335,26 @@ var deleteKubernetesSecuritygroupCmd = &cobra.Command{
 	Short:   "delete a kubernetes Securitygroup from your account.",
 	Example: "uthoctl kubernetes securitygroup delete <kubernetes-id> <securitygroup-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ParentID: args[0], ID: args[1]})
+		}
+
+		if err := helper.Confirm(); err != nil {
+			return err
 		}
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		securitygroup, err := client.Kubernetes().DeleteSecurityGroup(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + securitygroup.Status)
+		return printResult(cmd, securitygroup, "Status")
 	},
 }
 
@@ -366,8 +362,8 @@ var deleteKubernetesSecuritygroupCmd = &cobra.Command{
 var kubernetesTargetgroupCmd = &cobra.Command{
 	Use:   "targetgroup",
 	Short: "Use this command to manage kubernetes Target Group.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -376,74 +372,65 @@ var createKubernetesTargetgroupCmd = &cobra.Command{
 	Short:   "Create a kubernetes targetgroup.",
 	Example: "uthoctl kubernetes targetgroup create <kubernetes-id> <targetgroup-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
+	RunE: func(cmd *cobra.Command, args []string) error {
 		params := utho.CreateKubernetesTargetgroupParams{
 			KubernetesId:            args[0],
 			KubernetesTargetgroupId: args[1],
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		targetgroup, err := client.Kubernetes().CreateTargetgroup(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Targetgroup Name", "Targetgroup Id", "Status")
-		tbl.AddRow(args[1], targetgroup.ID, targetgroup.Status)
-		tbl.Print()
+		return printResult(cmd, targetgroup, "ID", "Status")
 	},
 }
 
 var getKubernetesTargetgroupCmd = &cobra.Command{
 	Use:     "get",
 	Short:   "Get kubernetes targetgroup info",
-	Example: "uthoctl kubernetes targetgroup get <kubernetes-id> <targetgroup-id",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	Example: "uthoctl kubernetes targetgroup get <kubernetes-id> <targetgroup-id>",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		targetgroup, err := client.Kubernetes().ReadTargetgroup(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name", "Protocol", "Port")
-		tbl.AddRow(targetgroup.ID, targetgroup.Name, targetgroup.Protocol, targetgroup.Port)
-		tbl.Print()
+		return printResult(cmd, targetgroup, "ID", "Name", "Protocol", "Port")
 	},
 }
 
 var listKubernetesTargetgroupCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List kubernetes policy",
-	Example: "uthoctl kubernetes policy list <kubernetes-id>",
-	Run: func(cmd *cobra.Command, args []string) {
+	Example: "uthoctl kubernetes targetgroup list <kubernetes-id>",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		targetgroups, err := client.Kubernetes().ListTargetgroups(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name", "Protocol", "Port")
-		for _, targetgroup := range targetgroups {
-			tbl.AddRow(targetgroup.ID, targetgroup.Name, targetgroup.Protocol, targetgroup.Port)
-		}
-		tbl.Print()
+		return printResult(cmd, targetgroups, "ID", "Name", "Protocol", "Port")
 	},
 }
 
@@ -452,26 +439,26 @@ var deleteKubernetesTargetgroupCmd = &cobra.Command{
 	Short:   "delete a kubernetes Targetgroup from your account.",
 	Example: "uthoctl kubernetes targetgroup delete <kubernetes-id> <targetgroup-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ParentID: args[0], ID: args[1]})
 		}
 
-		client, err := helper.NewUthoClient()
+		if err := helper.Confirm(); err != nil {
+			return err
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		targetgroup, err := client.Kubernetes().DeleteTargetgroup(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + targetgroup.Status)
+		return printResult(cmd, targetgroup, "Status")
 	},
 }
 
@@ -479,35 +466,61 @@ func init() {
 	rootCmd.AddCommand(kubernetesCmd)
 	// Kubernetes
 	kubernetesCmd.AddCommand(createKubernetesCmd)
+	addWaitFlags(createKubernetesCmd, "cluster is active")
 	createKubernetesCmd.Flags().String("dcslug", "", "")
-	createKubernetesCmd.Flags().String("cluster_label", "", "")
 	createKubernetesCmd.Flags().String("cluster_version", "", "")
 	createKubernetesCmd.Flags().String("auth", "", "")
 	createKubernetesCmd.Flags().String("vpc", "", "")
 	createKubernetesCmd.Flags().String("security_groups", "", "")
+	createKubernetesCmd.Flags().String("cluster_label", "", "Cluster label, overrides the name argument")
+	deprecateFlag(createKubernetesCmd, "cluster_label", "give the label as the name argument instead")
+	resolveFlagNames(createKubernetesCmd, "vpc", helper.KindVPC)
+	createKubernetesCmd.MarkFlagRequired("dcslug")
 
 	kubernetesCmd.AddCommand(getKubernetesCmd)
+	resolveNames(getKubernetesCmd, helper.KindCluster)
 	kubernetesCmd.AddCommand(listKubernetesCmd)
 	kubernetesCmd.AddCommand(deleteKubernetesCmd)
+	resolveNames(deleteKubernetesCmd, helper.KindCluster)
+	addWaitFlags(deleteKubernetesCmd, "cluster is deleted")
+	addSelector(deleteKubernetesCmd, clusterSelection, "delete", func(client utho.Client, c candidate) error {
+		_, err := client.Kubernetes().Delete(utho.DeleteKubernetesParams{
+			ClusterId: c.ID,
+			Confirm:   "I am aware this action will delete data and cluster permanently",
+		})
+		return err
+	})
 
 	// Loadbalancer
 	kubernetesCmd.AddCommand(kubernetesLoadbalancerCmd)
 	kubernetesLoadbalancerCmd.AddCommand(createKubernetesLoadbalancerCmd)
+	resolveNames(createKubernetesLoadbalancerCmd, helper.KindCluster, helper.KindLoadbalancer)
 	kubernetesLoadbalancerCmd.AddCommand(getKubernetesLoadbalancerCmd)
+	resolveNames(getKubernetesLoadbalancerCmd, helper.KindCluster, helper.KindLoadbalancer)
 	kubernetesLoadbalancerCmd.AddCommand(listKubernetesLoadbalancerCmd)
+	resolveNames(listKubernetesLoadbalancerCmd, helper.KindCluster)
 	kubernetesLoadbalancerCmd.AddCommand(deleteKubernetesLoadbalancerCmd)
+	resolveNames(deleteKubernetesLoadbalancerCmd, helper.KindCluster, helper.KindLoadbalancer)
 
 	// Securitygroup
 	kubernetesCmd.AddCommand(kubernetesecuritygroupCmd)
 	kubernetesecuritygroupCmd.AddCommand(createKubernetesSecuritygroupCmd)
+	resolveNames(createKubernetesSecuritygroupCmd, helper.KindCluster)
 	kubernetesecuritygroupCmd.AddCommand(getKubernetesSecuritygroupCmd)
+	resolveNames(getKubernetesSecuritygroupCmd, helper.KindCluster)
 	kubernetesecuritygroupCmd.AddCommand(listKubernetesSecuritygroupCmd)
+	resolveNames(listKubernetesSecuritygroupCmd, helper.KindCluster)
 	kubernetesecuritygroupCmd.AddCommand(deleteKubernetesSecuritygroupCmd)
+	resolveNames(deleteKubernetesSecuritygroupCmd, helper.KindCluster)
 
 	// Targetgroup
 	kubernetesCmd.AddCommand(kubernetesTargetgroupCmd)
 	kubernetesTargetgroupCmd.AddCommand(createKubernetesTargetgroupCmd)
+	resolveNames(createKubernetesTargetgroupCmd, helper.KindCluster, helper.KindTargetGroup)
 	kubernetesTargetgroupCmd.AddCommand(getKubernetesTargetgroupCmd)
+	resolveNames(getKubernetesTargetgroupCmd, helper.KindCluster, helper.KindTargetGroup)
 	kubernetesTargetgroupCmd.AddCommand(listKubernetesTargetgroupCmd)
+	resolveNames(listKubernetesTargetgroupCmd, helper.KindCluster)
 	kubernetesTargetgroupCmd.AddCommand(deleteKubernetesTargetgroupCmd)
+	resolveNames(deleteKubernetesTargetgroupCmd, helper.KindCluster, helper.KindTargetGroup)
 }
@@ -1,10 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-
-	"github.com/rodaine/table"
 	"github.com/spf13/cobra"
 	"github.com/uthoplatforms/utho-cli/helper"
 	"github.com/uthoplatforms/utho-go/utho"
@@ -13,8 +9,8 @@ import (
 var loadbalancerCmd = &cobra.Command{
 	Use:   "loadbalancer",
 	Short: "Use this command to manage loadbalancers.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -23,12 +19,7 @@ var createLoadbalancerCmd = &cobra.Command{
 	Short:   "Create a loadbalancer.",
 	Example: "uthoctl loadbalancer create <loadbalancer-name>",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
+	RunE: func(cmd *cobra.Command, args []string) error {
 		dcslug, _ := cmd.Flags().GetString("dcslug")
 		loadbalancerType, _ := cmd.Flags().GetString("type")
 
@@ -37,15 +28,24 @@ var createLoadbalancerCmd = &cobra.Command{
 			Dcslug: dcslug,
 			Type:   loadbalancerType,
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		loadbalancer, err := client.Loadbalancers().Create(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Loadbalancer Name", "Loadbalancer Id", "Status")
-		tbl.AddRow(args[0], loadbalancer.ID, loadbalancer.Status)
-		tbl.Print()
+		if err := printResult(cmd, loadbalancer, "ID", "Status"); err != nil {
+			return err
+		}
+		return waitFor(cmd, "load balancer "+loadbalancer.ID, waitReady, loadbalancerStatus(loadbalancer.ID))
 	},
 }
 
@@ -54,21 +54,17 @@ var getLoadbalancerCmd = &cobra.Command{
 	Short:   "Get loadbalancer info",
 	Example: "uthoctl loadbalancer get <loadbalancer-id>",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		loadbalancer, err := client.Loadbalancers().Read(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "IP", "Algorithm", "Type", "Status")
-		tbl.AddRow(loadbalancer.ID, loadbalancer.IP, loadbalancer.Algorithm, loadbalancer.Type, loadbalancer.Status)
-		tbl.Print()
+		return printResult(cmd, loadbalancer, "ID", "IP", "Algorithm", "Type", "Status")
 	},
 }
 
@@ -76,62 +72,69 @@ var listLoadbalancerCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List loadbalancer info",
 	Example: "uthoctl loadbalancer list",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		loadbalancers, err := client.Loadbalancers().List()
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "IP", "Algorithm", "Type", "Status")
-		for _, loadbalancer := range loadbalancers {
-			tbl.AddRow(loadbalancer.ID, loadbalancer.IP, loadbalancer.Algorithm, loadbalancer.Type, loadbalancer.Status)
-		}
-		tbl.Print()
+		return printResult(cmd, loadbalancers, "ID", "IP", "Algorithm", "Type", "Status")
 	},
 }
 
 var deleteLoadbalancerCmd = &cobra.Command{
 	Use:     "delete",
 	Short:   "delete a loadbalancer from your account.",
-	Example: "uthoctl loadbalancer delete <loadbalancer-id>",
+	Example: "uthoctl loadbalancer delete <loadbalancer-id>\nuthoctl loadbalancer delete --selector 'name=test-*'",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ID: args[0]})
 		}
 
-		client, err := helper.NewUthoClient()
+		if err := helper.Confirm(); err != nil {
+			return err
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		loadbalancer, err := client.Loadbalancers().Delete(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + loadbalancer.Status)
+		if err := printResult(cmd, loadbalancer, "Status"); err != nil {
+			return err
+		}
+		return waitFor(cmd, "load balancer "+args[0], waitGone, loadbalancerStatus(args[0]))
 	},
 }
 
+// loadbalancerStatus reads the status of the load balancer id, for --wait.
+func loadbalancerStatus(id string) statusReader {
+	return func(client utho.Client) (string, error) {
+		loadbalancer, err := client.Loadbalancers().Read(id)
+		if err != nil {
+			return "", err
+		}
+		return loadbalancer.Status, nil
+	}
+}
+
 // LoadbalancerAcl
 var loadbalancerAclCmd = &cobra.Command{
 	Use:   "acl",
 	Short: "Use this command to manage Loadbalancer ACL.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
-
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -140,13 +143,7 @@ var createLoadbalancerAclCmd = &cobra.Command{
 	Short:   "Create a Loadbalancer acl.",
 	Example: "uthoctl loadbalancer acl create <loadbalancer-id> <acl-name>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
+	RunE: func(cmd *cobra.Command, args []string) error {
 		conditionType, _ := cmd.Flags().GetString("condition_type")
 		frontendId, _ := cmd.Flags().GetString("frontend_id")
 		value, _ := cmd.Flags().GetString("value")
@@ -157,38 +154,40 @@ var createLoadbalancerAclCmd = &cobra.Command{
 			FrontendID:     frontendId,
 			Value:          value,
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		acl, err := client.Loadbalancers().CreateACL(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Acl Name", "Acl Id", "Status")
-		tbl.AddRow(args[1], acl.ID, acl.Status)
-		tbl.Print()
+		return printResult(cmd, acl, "ID", "Status")
 	},
 }
 
 var getLoadbalancerAclCmd = &cobra.Command{
 	Use:     "get",
 	Short:   "Get Loadbalancer acl info",
-	Example: "uthoctl loadbalancer acl get <loadbalancer-id> <acl-id",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	Example: "uthoctl loadbalancer acl get <loadbalancer-id> <acl-id>",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		acl, err := client.Loadbalancers().ReadACL(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name", "ACLCondition", "Value")
-		tbl.AddRow(acl.ID, acl.Name, acl.ACLCondition, acl.Value)
-		tbl.Print()
+		return printResult(cmd, acl, "ID", "Name", "ACLCondition", "Value")
 	},
 }
 
@@ -196,24 +195,19 @@ var listLoadbalancerAclCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List Loadbalancer acl",
 	Example: "uthoctl loadbalancer acl list <loadbalancer-id>",
-	Run: func(cmd *cobra.Command, args []string) {
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		acls, err := client.Loadbalancers().ListACLs(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name", "ACLCondition", "Value")
-		for _, acl := range acls {
-			tbl.AddRow(acl.ID, acl.Name, acl.ACLCondition, acl.Value)
-		}
-		tbl.Print()
+		return printResult(cmd, acls, "ID", "Name", "ACLCondition", "Value")
 	},
 }
 
@@ -222,26 +216,26 @@ var deleteLoadbalancerAclCmd = &cobra.Command{
 	Short:   "delete a Loadbalancer ACL from your account.",
 	Example: "uthoctl loadbalancer acl delete <loadbalancer-id> <acl-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ParentID: args[0], ID: args[1]})
+		}
+
+		if err := helper.Confirm(); err != nil {
+			return err
 		}
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		acl, err := client.Loadbalancers().DeleteACL(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + acl.Status)
+		return printResult(cmd, acl, "Status")
 	},
 }
 
@@ -249,9 +243,8 @@ var deleteLoadbalancerAclCmd = &cobra.Command{
 var loadbalancerFrontendCmd = &cobra.Command{
 	Use:   "frontend",
 	Short: "Use this command to manage Loadbalancer Frontend.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
-
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -260,13 +253,7 @@ var createLoadbalancerFrontendCmd = &cobra.Command{
 	Short:   "Create a Loadbalancer frontend.",
 	Example: "uthoctl loadbalancer frontend create <loadbalancer-id> <frontend-name>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
+	RunE: func(cmd *cobra.Command, args []string) error {
 		proto, _ := cmd.Flags().GetString("proto")
 		port, _ := cmd.Flags().GetString("port")
 		certificateId, _ := cmd.Flags().GetString("certificate_id")
@@ -283,38 +270,40 @@ var createLoadbalancerFrontendCmd = &cobra.Command{
 			Redirecthttps:  redirecthttps,
 			Cookie:         cookie,
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		frontend, err := client.Loadbalancers().CreateFrontend(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Frontend Name", "Frontend Id", "Status")
-		tbl.AddRow(args[1], frontend.ID, frontend.Status)
-		tbl.Print()
+		return printResult(cmd, frontend, "ID", "Status")
 	},
 }
 
 var getLoadbalancerFrontendCmd = &cobra.Command{
 	Use:     "get",
 	Short:   "Get Loadbalancer frontend info",
-	Example: "uthoctl loadbalancer frontend get <loadbalancer-id> <frontend-id",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	Example: "uthoctl loadbalancer frontend get <loadbalancer-id> <frontend-id>",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		frontend, err := client.Loadbalancers().ReadFrontend(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name", "Algorithm", "CertificateID", "Port")
-		tbl.AddRow(frontend.ID, frontend.Name, frontend.Algorithm, frontend.CertificateID, frontend.Port)
-		tbl.Print()
+		return printResult(cmd, frontend, "ID", "Name", "Algorithm", "CertificateID", "Port")
 	},
 }
 
@@ -322,24 +311,19 @@ var listLoadbalancerFrontendCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List Loadbalancer frontend",
 	Example: "uthoctl loadbalancer frontend list <loadbalancer-id>",
-	Run: func(cmd *cobra.Command, args []string) {
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		frontends, err := client.Loadbalancers().ListFrontends(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name", "Algorithm", "CertificateID", "Port")
-		for _, frontend := range frontends {
-			tbl.AddRow(frontend.ID, frontend.Name, frontend.Algorithm, frontend.CertificateID, frontend.Port)
-		}
-		tbl.Print()
+		return printResult(cmd, frontends, "ID", "Name", "Algorithm", "CertificateID", "Port")
 	},
 }
 
@@ -348,26 +332,26 @@ var deleteLoadbalancerFrontendCmd = &cobra.Command{
 	Short:   "delete a Loadbalancer Frontend from your account.",
 	Example: "uthoctl loadbalancer frontend delete <loadbalancer-id> <frontend-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ParentID: args[0], ID: args[1]})
 		}
 
-		client, err := helper.NewUthoClient()
+		if err := helper.Confirm(); err != nil {
+			return err
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		frontend, err := client.Loadbalancers().DeleteFrontend(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + frontend.Status)
+		return printResult(cmd, frontend, "Status")
 	},
 }
 
@@ -375,8 +359,8 @@ var deleteLoadbalancerFrontendCmd = &cobra.Command{
 var loadbalancerBackendCmd = &cobra.Command{
 	Use:   "backend",
 	Short: "Use this command to manage Loadbalancer Backend.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -385,13 +369,7 @@ var createLoadbalancerBackendCmd = &cobra.Command{
 	Short:   "Create a Loadbalancer backend.",
 	Example: "uthoctl loadbalancer backend create <loadbalancer-id> <frontend-id> <cloud-id>",
 	Args:    cobra.ExactArgs(3),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
+	RunE: func(cmd *cobra.Command, args []string) error {
 		port, _ := cmd.Flags().GetString("port")
 		params := utho.CreateLoadbalancerBackendParams{
 			LoadbalancerId: args[0],
@@ -399,38 +377,40 @@ var createLoadbalancerBackendCmd = &cobra.Command{
 			BackendPort:    port,
 			Cloudid:        args[2],
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		backend, err := client.Loadbalancers().CreateBackend(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Backend Name", "Backend Id", "Status")
-		tbl.AddRow(args[1], backend.ID, backend.Status)
-		tbl.Print()
+		return printResult(cmd, backend, "ID", "Status")
 	},
 }
 
 var getLoadbalancerBackendCmd = &cobra.Command{
 	Use:     "get",
 	Short:   "Get Loadbalancer backend info",
-	Example: "uthoctl loadbalancer backend get <loadbalancer-id> <backend-id",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	Example: "uthoctl loadbalancer backend get <loadbalancer-id> <backend-id>",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		backend, err := client.Loadbalancers().ReadBackend(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "IP", "Cloudid", "Name", "RAM", "CPU", "Disk")
-		tbl.AddRow(backend.ID, backend.IP, backend.Cloudid, backend.Name, backend.RAM, backend.CPU, backend.Disk)
-		tbl.Print()
+		return printResult(cmd, backend, "ID", "IP", "Cloudid", "Name", "RAM", "CPU", "Disk")
 	},
 }
 
@@ -438,24 +418,19 @@ var listLoadbalancerBackendCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List Loadbalancer backend",
 	Example: "uthoctl loadbalancer backend list <loadbalancer-id>",
-	Run: func(cmd *cobra.Command, args []string) {
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		backends, err := client.Loadbalancers().ListBackends(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "IP", "Cloudid", "Name", "RAM", "CPU", "Disk")
-		for _, backend := range backends {
-			tbl.AddRow(backend.ID, backend.IP, backend.Cloudid, backend.Name, backend.RAM, backend.CPU, backend.Disk)
-		}
-		tbl.Print()
+		return printResult(cmd, backends, "ID", "IP", "Cloudid", "Name", "RAM", "CPU", "Disk")
 	},
 }
 
@@ -464,26 +439,26 @@ var deleteLoadbalancerBackendCmd = &cobra.Command{
 	Short:   "delete a Loadbalancer Backend from your account.",
 	Example: "uthoctl loadbalancer backend delete <loadbalancer-id> <backend-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ParentID: args[0], ID: args[1]})
 		}
 
-		client, err := helper.NewUthoClient()
+		if err := helper.Confirm(); err != nil {
+			return err
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		backend, err := client.Loadbalancers().DeleteBackend(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + backend.Status)
+		return printResult(cmd, backend, "Status")
 	},
 }
 
@@ -491,8 +466,8 @@ var deleteLoadbalancerBackendCmd = &cobra.Command{
 var loadbalancerRouteCmd = &cobra.Command{
 	Use:   "route",
 	Short: "Use this command to manage Loadbalancer Route.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -501,13 +476,7 @@ var createLoadbalancerRouteCmd = &cobra.Command{
 	Short:   "Create a Loadbalancer route.",
 	Example: "uthoctl loadbalancer route create <loadbalancer-id> <frontend-id> <acl-id>",
 	Args:    cobra.ExactArgs(3),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
+	RunE: func(cmd *cobra.Command, args []string) error {
 		routeCondition, _ := cmd.Flags().GetString("route_condition")
 		targetGroups, _ := cmd.Flags().GetString("target_groups")
 		params := utho.CreateLoadbalancerRouteParams{
@@ -517,38 +486,40 @@ var createLoadbalancerRouteCmd = &cobra.Command{
 			RouteCondition: routeCondition,
 			TargetGroups:   targetGroups,
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		route, err := client.Loadbalancers().CreateRoute(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Route Id", "Status")
-		tbl.AddRow(route.ID, route.Status)
-		tbl.Print()
+		return printResult(cmd, route, "ID", "Status")
 	},
 }
 
 var getLoadbalancerRouteCmd = &cobra.Command{
 	Use:     "get",
 	Short:   "Get Loadbalancer route info",
-	Example: "uthoctl loadbalancer route get <loadbalancer-id> <route-id",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	Example: "uthoctl loadbalancer route get <loadbalancer-id> <route-id>",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		route, err := client.Loadbalancers().ReadRoute(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "ACLID", "ACLName", "RoutingCondition", "BackendID")
-		tbl.AddRow(route.ID, route.ACLID, route.ACLName, route.RoutingCondition, route.BackendID)
-		tbl.Print()
+		return printResult(cmd, route, "ID", "ACLID", "ACLName", "RoutingCondition", "BackendID")
 	},
 }
 
@@ -556,24 +527,19 @@ var listLoadbalancerRouteCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List Loadbalancer route",
 	Example: "uthoctl loadbalancer route list <loadbalancer-id>",
-	Run: func(cmd *cobra.Command, args []string) {
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		routes, err := client.Loadbalancers().ListRoutes(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "ACLID", "ACLName", "RoutingCondition", "BackendID")
-		for _, route := range routes {
-			tbl.AddRow(route.ID, route.ACLID, route.ACLName, route.RoutingCondition, route.BackendID)
-		}
-		tbl.Print()
+		return printResult(cmd, routes, "ID", "ACLID", "ACLName", "RoutingCondition", "BackendID")
 	},
 }
 
@@ -582,26 +548,26 @@ var deleteLoadbalancerRouteCmd = &cobra.Command{
 	Short:   "delete a Loadbalancer Route from your account.",
 	Example: "uthoctl loadbalancer route delete <loadbalancer-id> <route-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ParentID: args[0], ID: args[1]})
+		}
+
+		if err := helper.Confirm(); err != nil {
+			return err
 		}
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		route, err := client.Loadbalancers().DeleteRoute(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + route.Status)
+		return printResult(cmd, route, "Status")
 	},
 }
 
@@ -609,55 +575,86 @@ func init() {
 	rootCmd.AddCommand(loadbalancerCmd)
 	// Loadbalancer
 	loadbalancerCmd.AddCommand(createLoadbalancerCmd)
+	addWaitFlags(createLoadbalancerCmd, "load balancer is active")
 	createLoadbalancerCmd.Flags().String("dcslug", "", "Provide Zone dcslug eg: innoida")
 	createLoadbalancerCmd.Flags().String("type", "", "Load-Balancer type must be either application or network. The default value is application")
+	ignoredFlag(createLoadbalancerCmd, "route_condition", "it was never sent to the API, use it with loadbalancer route create")
+	ignoredFlag(createLoadbalancerCmd, "target_groups", "it was never sent to the API, use it with loadbalancer route create")
+	createLoadbalancerCmd.MarkFlagRequired("dcslug")
 
 	loadbalancerCmd.AddCommand(getLoadbalancerCmd)
+	resolveNames(getLoadbalancerCmd, helper.KindLoadbalancer)
 	loadbalancerCmd.AddCommand(listLoadbalancerCmd)
 	loadbalancerCmd.AddCommand(deleteLoadbalancerCmd)
+	resolveNames(deleteLoadbalancerCmd, helper.KindLoadbalancer)
+	addWaitFlags(deleteLoadbalancerCmd, "load balancer is deleted")
+	addSelector(deleteLoadbalancerCmd, loadbalancerSelection, "delete", func(client utho.Client, c candidate) error {
+		_, err := client.Loadbalancers().Delete(c.ID)
+		return err
+	})
 
 	// acl
 	loadbalancerCmd.AddCommand(loadbalancerAclCmd)
 	loadbalancerAclCmd.AddCommand(createLoadbalancerAclCmd)
+	resolveNames(createLoadbalancerAclCmd, helper.KindLoadbalancer)
 	createLoadbalancerAclCmd.Flags().String("condition_type", "", "")
 	createLoadbalancerAclCmd.Flags().String("frontend_id", "", "")
 	createLoadbalancerAclCmd.Flags().String("value", "", "")
+	createLoadbalancerAclCmd.MarkFlagRequired("condition_type")
+	createLoadbalancerAclCmd.MarkFlagRequired("frontend_id")
 
 	loadbalancerAclCmd.AddCommand(getLoadbalancerAclCmd)
+	resolveNames(getLoadbalancerAclCmd, helper.KindLoadbalancer)
 	loadbalancerAclCmd.AddCommand(listLoadbalancerAclCmd)
+	resolveNames(listLoadbalancerAclCmd, helper.KindLoadbalancer)
 	loadbalancerAclCmd.AddCommand(deleteLoadbalancerAclCmd)
+	resolveNames(deleteLoadbalancerAclCmd, helper.KindLoadbalancer)
 
 	// Frontend
 	loadbalancerCmd.AddCommand(loadbalancerFrontendCmd)
 	loadbalancerFrontendCmd.AddCommand(createLoadbalancerFrontendCmd)
+	resolveNames(createLoadbalancerFrontendCmd, helper.KindLoadbalancer)
 	createLoadbalancerFrontendCmd.Flags().String("proto", "", "")
 	createLoadbalancerFrontendCmd.Flags().String("port", "", "")
 	createLoadbalancerFrontendCmd.Flags().String("certificate_id", "", "")
 	createLoadbalancerFrontendCmd.Flags().String("algorithm", "", "")
 	createLoadbalancerFrontendCmd.Flags().String("redirecthttps", "", "")
 	createLoadbalancerFrontendCmd.Flags().String("cookie", "", "")
+	createLoadbalancerFrontendCmd.MarkFlagRequired("proto")
+	createLoadbalancerFrontendCmd.MarkFlagRequired("port")
 
 	loadbalancerFrontendCmd.AddCommand(getLoadbalancerFrontendCmd)
+	resolveNames(getLoadbalancerFrontendCmd, helper.KindLoadbalancer)
 	loadbalancerFrontendCmd.AddCommand(listLoadbalancerFrontendCmd)
+	resolveNames(listLoadbalancerFrontendCmd, helper.KindLoadbalancer)
 	loadbalancerFrontendCmd.AddCommand(deleteLoadbalancerFrontendCmd)
+	resolveNames(deleteLoadbalancerFrontendCmd, helper.KindLoadbalancer)
 
 	// Backend
 	loadbalancerCmd.AddCommand(loadbalancerBackendCmd)
 	loadbalancerBackendCmd.AddCommand(createLoadbalancerBackendCmd)
+	resolveNames(createLoadbalancerBackendCmd, helper.KindLoadbalancer, "", helper.KindInstance)
 	createLoadbalancerBackendCmd.Flags().String("port", "", "")
 
 	loadbalancerBackendCmd.AddCommand(getLoadbalancerBackendCmd)
+	resolveNames(getLoadbalancerBackendCmd, helper.KindLoadbalancer)
 	loadbalancerBackendCmd.AddCommand(listLoadbalancerBackendCmd)
+	resolveNames(listLoadbalancerBackendCmd, helper.KindLoadbalancer)
 	loadbalancerBackendCmd.AddCommand(deleteLoadbalancerBackendCmd)
+	resolveNames(deleteLoadbalancerBackendCmd, helper.KindLoadbalancer)
 
 	// Route
 	loadbalancerCmd.AddCommand(loadbalancerRouteCmd)
 	loadbalancerRouteCmd.AddCommand(createLoadbalancerRouteCmd)
-	createLoadbalancerCmd.Flags().String("route_condition", "", "")
-	createLoadbalancerCmd.Flags().String("target_groups", "", "")
+	resolveNames(createLoadbalancerRouteCmd, helper.KindLoadbalancer)
+	createLoadbalancerRouteCmd.Flags().String("route_condition", "", "")
+	createLoadbalancerRouteCmd.Flags().String("target_groups", "", "")
 
 	loadbalancerRouteCmd.AddCommand(getLoadbalancerRouteCmd)
+	resolveNames(getLoadbalancerRouteCmd, helper.KindLoadbalancer)
 	loadbalancerRouteCmd.AddCommand(listLoadbalancerRouteCmd)
+	resolveNames(listLoadbalancerRouteCmd, helper.KindLoadbalancer)
 	loadbalancerRouteCmd.AddCommand(deleteLoadbalancerRouteCmd)
+	resolveNames(deleteLoadbalancerRouteCmd, helper.KindLoadbalancer)
 
 }
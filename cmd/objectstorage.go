@@ -1,10 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-
-	"github.com/rodaine/table"
 	"github.com/spf13/cobra"
 	"github.com/uthoplatforms/utho-cli/helper"
 	"github.com/uthoplatforms/utho-go/utho"
@@ -13,8 +9,8 @@ import (
 var objectstorageCmd = &cobra.Command{
 	Use:   "objectstorage",
 	Short: "Use this command to manage object storages.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -23,12 +19,7 @@ var createObjectstorageCmd = &cobra.Command{
 	Short:   "Create a objectstorage.",
 	Example: "uthoctl objectstorage create <objectstorage-name>",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
+	RunE: func(cmd *cobra.Command, args []string) error {
 		dcslug, _ := cmd.Flags().GetString("dcslug")
 		billing, _ := cmd.Flags().GetString("billing")
 		size, _ := cmd.Flags().GetString("size")
@@ -41,15 +32,21 @@ var createObjectstorageCmd = &cobra.Command{
 			Size:    size,
 			Price:   price,
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		objectstorage, err := client.ObjectStorage().CreateBucket(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Bucket Name", "Bucket Id", "Status")
-		tbl.AddRow(args[0], objectstorage.ID, objectstorage.Status)
-		tbl.Print()
+		return printResult(cmd, objectstorage, "ID", "Status")
 	},
 }
 
@@ -58,21 +55,17 @@ var getObjectstorageCmd = &cobra.Command{
 	Short:   "Get objectstorage info",
 	Example: "uthoctl objectstorage get <location-slug> <bucket-name>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		bucket, err := client.ObjectStorage().ReadBucket(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Name", "Dcslug", "Size", "Status", "ObjectCount", "CurrentSize")
-		tbl.AddRow(bucket.Name, bucket.Dcslug, bucket.Size, bucket.Status, bucket.ObjectCount, bucket.CurrentSize)
-		tbl.Print()
+		return printResult(cmd, bucket, "Name", "Dcslug", "Size", "Status", "ObjectCount", "CurrentSize")
 	},
 }
 
@@ -81,24 +74,18 @@ var listObjectstorageCmd = &cobra.Command{
 	Short:   "List objectstorage info",
 	Example: "uthoctl objectstorage list <location-slug>",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		buckets, err := client.ObjectStorage().ListBuckets(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Name", "Dcslug", "Size", "Status", "ObjectCount", "CurrentSize")
-		for _, bucket := range buckets {
-			tbl.AddRow(bucket.Name, bucket.Dcslug, bucket.Size, bucket.Status, bucket.ObjectCount, bucket.CurrentSize)
-		}
-		tbl.Print()
+		return printResult(cmd, buckets, "Name", "Dcslug", "Size", "Status", "ObjectCount", "CurrentSize")
 	},
 }
 
@@ -107,26 +94,26 @@ var deleteObjectstorageCmd = &cobra.Command{
 	Short:   "delete a objectstorage from your account.",
 	Example: "uthoctl objectstorage delete <location-slug> <bucket-name>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{Dcslug: args[0], Name: args[1]})
 		}
 
-		client, err := helper.NewUthoClient()
+		if err := helper.ConfirmDelete("bucket", args[1], nil); err != nil {
+			return err
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		objectstorage, err := client.ObjectStorage().DeleteBucket(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + objectstorage.Status)
+		return printResult(cmd, objectstorage, "Status")
 	},
 }
 
@@ -134,8 +121,8 @@ var deleteObjectstorageCmd = &cobra.Command{
 var accesskeyCmd = &cobra.Command{
 	Use:   "accesskey",
 	Short: "Use this command to manage objectstorage Accesskey.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -144,74 +131,65 @@ var createAccesskeyCmd = &cobra.Command{
 	Short:   "Create an objectstorage accesskey.",
 	Example: "uthoctl objectstorage accesskey create <location-slug> <accesskey-name>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
+	RunE: func(cmd *cobra.Command, args []string) error {
 		params := utho.CreateAccessKeyParams{
 			Dcslug:        args[0],
 			AccesskeyName: args[1],
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		accesskey, err := client.ObjectStorage().CreateAccessKey(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Accesskey Name", "Status")
-		tbl.AddRow(args[1], accesskey.Status)
-		tbl.Print()
+		return printResult(cmd, accesskey, "Status")
 	},
 }
 
 var getAccesskeyCmd = &cobra.Command{
 	Use:     "get",
 	Short:   "Get objectstorage accesskey info",
-	Example: "uthoctl objectstorage accesskey get <location-slug> <accesskey-id",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	Example: "uthoctl objectstorage accesskey get <location-slug> <accesskey-name>",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		accesskey, err := client.ObjectStorage().ReadAccessKey(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Name", "Accesskey", "Dcslug", "Status", "CreatedAt")
-		tbl.AddRow(accesskey.Name, accesskey.Accesskey, accesskey.Dcslug, accesskey.Status, accesskey.CreatedAt)
-		tbl.Print()
+		return printResult(cmd, accesskey, "Name", "Accesskey", "Dcslug", "Status", "CreatedAt")
 	},
 }
 
 var listAccesskeyCmd = &cobra.Command{
 	Use:     "list",
-	Short:   "List objectstorage policy",
-	Example: "uthoctl objectstorage policy list <objectstorage-id>",
-	Run: func(cmd *cobra.Command, args []string) {
+	Short:   "List objectstorage accesskeys",
+	Example: "uthoctl objectstorage accesskey list <location-slug>",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		accesskeys, err := client.ObjectStorage().ListAccessKeys(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Name", "Accesskey", "Dcslug", "Status", "CreatedAt")
-		for _, accesskey := range accesskeys {
-			tbl.AddRow(accesskey.Name, accesskey.Accesskey, accesskey.Dcslug, accesskey.Status, accesskey.CreatedAt)
-		}
-		tbl.Print()
+		return printResult(cmd, accesskeys, "Name", "Accesskey", "Dcslug", "Status", "CreatedAt")
 	},
 }
 
@@ -220,14 +198,14 @@ var listAccesskeyCmd = &cobra.Command{
 // 	Short:   "delete an objectstorage Accesskey from your account.",
 // 	Example: "uthoctl objectstorage accesskey delete <objectstorage-id> <accesskey-id>",
 // 	Args:    cobra.ExactArgs(2),
-// 	Run: func(cmd *cobra.Command, args []string) {
+// 	RunE: func(cmd *cobra.Command, args []string) error {
 // 		confirm := helper.Ask()
 // 		if !confirm {
 // 			fmt.Println("Operation aborted.")
 // 			os.Exit(1)
 // 		}
 
-// 		client, err := helper.NewUthoClient()
+// 		client, err := helper.NewUthoClient(cmd.Context())
 // 		if err != nil {
 // 			fmt.Println(err)
 // 			os.Exit(1)
@@ -248,9 +226,14 @@ func init() {
 	// Objectstorage
 	objectstorageCmd.AddCommand(createObjectstorageCmd)
 	createObjectstorageCmd.Flags().String("dcslug", "", "Provide Zone dcslug eg: innoida")
-	createObjectstorageCmd.Flags().String("Billing", "", "")
-	createObjectstorageCmd.Flags().String("Size", "", "")
-	createObjectstorageCmd.Flags().String("Price", "", "")
+	createObjectstorageCmd.Flags().String("billing", "", "Billing cycle eg: monthly")
+	createObjectstorageCmd.Flags().String("size", "", "Bucket size in GB")
+	createObjectstorageCmd.Flags().String("price", "", "")
+	renamedFlag(createObjectstorageCmd, "Billing", "billing")
+	renamedFlag(createObjectstorageCmd, "Size", "size")
+	renamedFlag(createObjectstorageCmd, "Price", "price")
+	createObjectstorageCmd.MarkFlagRequired("dcslug")
+	createObjectstorageCmd.MarkFlagRequired("size")
 
 	objectstorageCmd.AddCommand(getObjectstorageCmd)
 	objectstorageCmd.AddCommand(listObjectstorageCmd)
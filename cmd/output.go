@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+	"github.com/uthoplatforms/utho-cli/helper"
+	"github.com/uthoplatforms/utho-cli/printer"
+)
+
+// newPrinter builds a printer from the global output flags.
+func newPrinter(cmd *cobra.Command) (*printer.Printer, error) {
+	format, _ := cmd.Flags().GetString("output")
+	p, err := printer.New(format, cmd.OutOrStdout())
+	if err != nil {
+		return nil, err
+	}
+
+	p.Columns, _ = cmd.Flags().GetStringSlice("columns")
+	p.Wide, _ = cmd.Flags().GetBool("wide")
+	p.NoHeaders, _ = cmd.Flags().GetBool("no-headers")
+	p.SortBy, _ = cmd.Flags().GetString("sort-by")
+	return p, nil
+}
+
+// printResult renders v in the format selected with --output. columns are
+// the struct fields shown in table mode unless --columns or --wide is set.
+func printResult(cmd *cobra.Command, v any, columns ...string) error {
+	p, err := newPrinter(cmd)
+	if err != nil {
+		return helper.UsageError(err)
+	}
+	return printError(p.Print(v, columns...))
+}
+
+// printError makes the errors of the output expressions that do not apply
+// to the printed value, such as a jsonpath naming a missing field, usage
+// errors.
+func printError(err error) error {
+	var exprErr *printer.ExprError
+	if errors.As(err, &exprErr) {
+		return helper.UsageError(err)
+	}
+	return err
+}
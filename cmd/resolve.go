@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/uthoplatforms/utho-cli/helper"
+	"github.com/uthoplatforms/utho-go/utho"
+)
+
+// kindAnnotation holds the kinds of resources named by the positional
+// arguments of a command, comma separated, or by a flag.
+const kindAnnotation = "uthoctl_kind"
+
+// resolveNames makes cmd accept names in place of IDs for its positional
+// arguments. kinds gives the kind of each argument, "" for the ones taken as
+// they are.
+func resolveNames(cmd *cobra.Command, kinds ...string) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[kindAnnotation] = strings.Join(kinds, ",")
+}
+
+// resolveFlagNames makes the flag of cmd accept a name in place of the ID of
+// a resource of kind.
+func resolveFlagNames(cmd *cobra.Command, flag, kind string) {
+	cmd.Flags().SetAnnotation(flag, kindAnnotation, []string{kind})
+}
+
+// resolveArgs replaces the names given for the arguments and flags set up
+// with resolveNames by the IDs of the resources. args are changed in place,
+// the slice cobra then passes to RunE.
+func resolveArgs(cmd *cobra.Command, args []string) error {
+	var client utho.Client
+	resolve := func(kind, ref string) (string, error) {
+		if kind == "" || helper.IsID(ref) {
+			return ref, nil
+		}
+		if client == nil {
+			c, err := helper.NewUthoClient(cmd.Context())
+			if err != nil {
+				return "", err
+			}
+			client = c
+		}
+		return helper.Resolve(client, kind, ref)
+	}
+
+	if kinds, ok := cmd.Annotations[kindAnnotation]; ok {
+		for i, kind := range strings.Split(kinds, ",") {
+			if i >= len(args) {
+				break
+			}
+			id, err := resolve(kind, args[i])
+			if err != nil {
+				return err
+			}
+			args[i] = id
+		}
+	}
+
+	var err error
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		kinds := f.Annotations[kindAnnotation]
+		if err != nil || !f.Changed || len(kinds) == 0 {
+			return
+		}
+		var id string
+		if id, err = resolve(kinds[0], f.Value.String()); err == nil {
+			err = f.Value.Set(id)
+		}
+	})
+	return err
+}
@@ -1,45 +1,223 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"strings"
+	"syscall"
 
-	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"github.com/uthoplatforms/utho-cli/helper"
+	"github.com/uthoplatforms/utho-cli/printer"
 )
 
+// commandStarted is set once cobra has parsed and validated the arguments,
+// errors returned before that are usage errors.
+var commandStarted bool
+
+// cancelTimeout releases the context of --timeout.
+var cancelTimeout = func() {}
+
 var rootCmd = &cobra.Command{
-	Use:   "uthoctl",
-	Short: "uthoctl is a command line interface (CLI) for the Utho API.",
+	Use:           "uthoctl",
+	Short:         "uthoctl is a command line interface (CLI) for the Utho API.",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		commandStarted = true
+		warnDeprecatedFlags(cmd)
+		// cobra checks the flag groups after this hook, too late to report
+		// them as usage errors.
+		if err := cmd.ValidateFlagGroups(); err != nil {
+			return helper.UsageError(err)
+		}
+		if err := applyContextDefaults(cmd); err != nil {
+			return helper.UsageError(err)
+		}
+		// Nor the required flags, which context defaults may set.
+		if err := cmd.ValidateRequiredFlags(); err != nil {
+			return helper.UsageError(err)
+		}
+		if timeout := viper.GetDuration("timeout"); timeout > 0 {
+			ctx, cancel := context.WithTimeoutCause(cmd.Context(), timeout, fmt.Errorf("%w after %s", helper.ErrTimeout, timeout))
+			cmd.SetContext(ctx)
+			cancelTimeout = cancel
+		} else if timeout < 0 {
+			return helper.UsageError(fmt.Errorf("invalid timeout %s", timeout))
+		}
+		if _, err := newPrinter(cmd); err != nil {
+			return helper.UsageError(err)
+		}
+		if err := checkWaitFlags(cmd); err != nil {
+			return helper.UsageError(err)
+		}
+		return resolveArgs(cmd, args)
+	},
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		if show, _ := cmd.Flags().GetBool("show-action"); show {
+			for _, id := range helper.TriggeredActions() {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Action ID: %s\n", id)
+			}
+		}
+		return nil
+	},
 }
 
+// Execute runs the command line and exits with the code helper.ExitCode
+// maps its error to.
 func Execute() {
-	err := rootCmd.Execute()
-	if err != nil {
-		os.Exit(1)
-	}
+	os.Exit(execute())
 }
 
-func init() {
-	cobra.OnInitialize(initConfig)
-	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
-}
+// execute runs rootCmd and reports its error on stderr. The first SIGINT or
+// SIGTERM cancels the command context, which aborts the pending API request,
+// a second one kills the process.
+func execute() int {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		select {
+		case <-signals:
+			signal.Stop(signals)
+			cancel(helper.ErrCanceled)
+		case <-ctx.Done():
+		}
+	}()
 
-func initConfig() {
-	homeDir, err := homedir.Dir()
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	commandStarted = false
+	helper.ResetTriggeredActions()
+	bulkAudit.ids, bulkAudit.results = nil, nil
+	cancelTimeout = func() {}
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	defer cancelTimeout()
+	if err == nil {
+		recordAudit(cmd, nil)
+		return helper.ExitOK
+	}
+	if !commandStarted {
+		err = helper.UsageError(err)
+	}
+	// The error of a cancelled request only says the context is done.
+	if ctx := cmd.Context(); ctx != nil && context.Cause(ctx) != nil {
+		err = context.Cause(ctx)
 	}
-	configDir := filepath.Join(homeDir, ".config")
-	viper.SetConfigName("uthoctl")
-	viper.AddConfigPath(configDir)
-	viper.SetConfigType("yaml")
-
-	err = viper.ReadInConfig()
-	if err != nil {
-		fmt.Println("Unable to initialize Utho API client with new token: (hint: run 'uthoctl auth init')")
+	if commandStarted {
+		recordAudit(cmd, err)
 	}
+
+	code := helper.ExitCode(err)
+	if errors.Is(err, helper.ErrChangesPending) {
+		return code
+	}
+	fmt.Fprintln(cmd.ErrOrStderr(), "Error:", err)
+	if code == helper.ExitUsage {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Run '%s --help' for usage.\n", cmd.CommandPath())
+	}
+	return code
+}
+
+// deprecatedAnnotation is the annotation of the flags kept for earlier
+// versions, holding what to do instead.
+const deprecatedAnnotation = "uthoctl_deprecated"
+
+// deprecateFlag hides the flag name of cmd from the help, and has the
+// command warn on stderr that it is deprecated when it is used.
+func deprecateFlag(cmd *cobra.Command, name, message string) {
+	cmd.Flags().SetAnnotation(name, deprecatedAnnotation, []string{message})
+	cmd.Flags().MarkHidden(name)
+}
+
+// warnDeprecatedFlags warns about the deprecated flags set on cmd.
+func warnDeprecatedFlags(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if message, ok := f.Annotations[deprecatedAnnotation]; ok && f.Changed {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Flag --%s is deprecated, %s\n", f.Name, message[0])
+		}
+	})
+}
+
+// aliasValue is the value of a renamed flag, which sets the flag that
+// replaced it.
+type aliasValue struct {
+	flags *pflag.FlagSet
+	name  string
+}
+
+func (v *aliasValue) Set(s string) error { return v.flags.Set(v.name, s) }
+func (v *aliasValue) String() string     { return v.flags.Lookup(v.name).Value.String() }
+func (v *aliasValue) Type() string       { return v.flags.Lookup(v.name).Value.Type() }
+
+// renamedFlag keeps old, the name of the flag name in earlier versions,
+// working as a deprecated alias. name must be defined first.
+func renamedFlag(cmd *cobra.Command, old, name string) {
+	cmd.Flags().AddFlag(&pflag.Flag{
+		Name:  old,
+		Value: &aliasValue{flags: cmd.Flags(), name: name},
+		Usage: "Deprecated alias of --" + name,
+	})
+	deprecateFlag(cmd, old, "use --"+name+" instead")
+}
+
+// ignoredFlag keeps accepting old, a flag earlier versions defined but never
+// used, with a warning explaining what to do instead.
+func ignoredFlag(cmd *cobra.Command, old, message string) {
+	cmd.Flags().String(old, "", "Ignored")
+	deprecateFlag(cmd, old, message)
+}
+
+func init() {
+	rootCmd.PersistentFlags().String("config", "", "Config file (default $HOME/.config/uthoctl.yaml)")
+	viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config"))
+	viper.BindEnv("config", "UTHO_CONFIG")
+	rootCmd.PersistentFlags().String("token", "", "API token, overrides UTHO_TOKEN and the config file")
+	viper.BindPFlag("token", rootCmd.PersistentFlags().Lookup("token"))
+	rootCmd.PersistentFlags().String("api-url", "", "Utho API endpoint (default https://api.utho.com/v2/)")
+	viper.BindPFlag("api_url", rootCmd.PersistentFlags().Lookup("api-url"))
+	viper.BindEnv("api_url", "UTHO_API_URL")
+	rootCmd.PersistentFlags().Bool("verbose", false, "Log each API request with its status and duration to stderr")
+	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
+	rootCmd.PersistentFlags().Bool("debug", false, "Log API requests and responses with headers and bodies to stderr")
+	viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug"))
+	viper.BindEnv("debug", "UTHO_DEBUG")
+	rootCmd.PersistentFlags().Int("retries", helper.DefaultRetries, "Number of retries of requests failing with a 429, a 5xx or a connection error")
+	viper.BindPFlag("retries", rootCmd.PersistentFlags().Lookup("retries"))
+	viper.BindEnv("retries", "UTHO_RETRIES")
+	rootCmd.PersistentFlags().Bool("retry-mutating", false, "Also retry create, update and delete requests, which may then run twice")
+	viper.BindPFlag("retry_mutating", rootCmd.PersistentFlags().Lookup("retry-mutating"))
+	viper.BindEnv("retry_mutating", "UTHO_RETRY_MUTATING")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "Give up on the command after this long, eg: 30s, 5m (default no limit)")
+	viper.BindPFlag("timeout", rootCmd.PersistentFlags().Lookup("timeout"))
+	viper.BindEnv("timeout", "UTHO_TIMEOUT")
+	rootCmd.PersistentFlags().String("record", "", "Record the API requests and responses to a cassette in this directory, with the token scrubbed")
+	viper.BindPFlag("record", rootCmd.PersistentFlags().Lookup("record"))
+	rootCmd.PersistentFlags().String("replay", "", "Serve the API responses from the cassette in this directory instead of the network")
+	viper.BindPFlag("replay", rootCmd.PersistentFlags().Lookup("replay"))
+	rootCmd.MarkFlagsMutuallyExclusive("record", "replay")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Print the request of create, delete and other mutating commands instead of sending it")
+	viper.BindPFlag("dry_run", rootCmd.PersistentFlags().Lookup("dry-run"))
+
+	rootCmd.PersistentFlags().BoolP("yes", "y", false, "Skip confirmation prompts of destructive commands, required when stdin is not a terminal")
+	viper.BindPFlag("yes", rootCmd.PersistentFlags().Lookup("yes"))
+	viper.BindEnv("yes", "UTHO_YES")
+
+	rootCmd.PersistentFlags().Bool("show-action", false, "Print the ID of the actions started by the command on stderr, when the API reports them")
+	rootCmd.PersistentFlags().String("context", "", "Context to use instead of the current one")
+	viper.BindPFlag("context", rootCmd.PersistentFlags().Lookup("context"))
+	viper.BindEnv("context", "UTHO_CONTEXT")
+	viper.BindEnv("audit_log", "UTHO_AUDIT_LOG")
+	rootCmd.PersistentFlags().StringP("output", "o", printer.FormatTable, "Output format: "+strings.Join(printer.Formats, ", "))
+	rootCmd.PersistentFlags().StringSlice("columns", nil, "Comma separated fields to show in tables, eg: ID,Hostname,Status")
+	rootCmd.PersistentFlags().Bool("wide", false, "Show every field in tables")
+	rootCmd.PersistentFlags().Bool("no-headers", false, "Do not print table headers")
+	rootCmd.PersistentFlags().String("sort-by", "", "Sort lists by the given field, eg: Hostname")
+	rootCmd.MarkFlagsMutuallyExclusive("columns", "wide")
+	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/uthoplatforms/utho-cli/helper"
+)
+
+// resetFlags restores every flag of cmd and its subcommands to its default,
+// as cobra keeps the values parsed by the previous run.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if _, ok := f.Value.(*aliasValue); ok {
+			f.Changed = false
+			return
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			sv.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// resetContexts drops the context cobra kept on every command from the
+// previous run, which is cancelled by now.
+func resetContexts(cmd *cobra.Command) {
+	cmd.SetContext(nil)
+	for _, sub := range cmd.Commands() {
+		resetContexts(sub)
+	}
+}
+
+func TestExecuteExitCodes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("UTHO_TOKEN", "")
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{"unknown command", []string{"bogus"}, helper.ExitUsage, `unknown command "bogus"`},
+		{"unknown flag", []string{"instance", "list", "--bogus"}, helper.ExitUsage, "unknown flag: --bogus"},
+		{"missing argument", []string{"instance", "get"}, helper.ExitUsage, "accepts 1 arg(s), received 0"},
+		{"invalid output", []string{"instance", "list", "-o", "xml"}, helper.ExitUsage, `unknown output format "xml"`},
+		{"no token", []string{"instance", "list"}, helper.ExitAuth, "no token found"},
+		{"unknown context", []string{"context", "use", "prod"}, helper.ExitNotFound, `context "prod" not found`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(rootCmd)
+			resetContexts(rootCmd)
+			var stdout, stderr bytes.Buffer
+			rootCmd.SetOut(&stdout)
+			rootCmd.SetErr(&stderr)
+			rootCmd.SetArgs(tt.args)
+			defer rootCmd.SetArgs(nil)
+
+			if code := execute(); code != tt.code {
+				t.Errorf("exit code = %d, want %d (stderr: %s)", code, tt.code, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr.String(), tt.stderr)
+			}
+			if stdout.Len() != 0 {
+				t.Errorf("stdout = %q, want it empty", stdout.String())
+			}
+		})
+	}
+}
@@ -1,10 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-
-	"github.com/rodaine/table"
 	"github.com/spf13/cobra"
 	"github.com/uthoplatforms/utho-cli/helper"
 	"github.com/uthoplatforms/utho-go/utho"
@@ -13,8 +9,8 @@ import (
 var targetgroupCmd = &cobra.Command{
 	Use:   "targetgroup",
 	Short: "Use this command to manage target group.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -23,12 +19,7 @@ var createTargetgroupCmd = &cobra.Command{
 	Short:   "Create a targetgroup.",
 	Example: "uthoctl targetgroup create <targetgroup-name>",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
+	RunE: func(cmd *cobra.Command, args []string) error {
 		protocol, _ := cmd.Flags().GetString("protocol")
 		port, _ := cmd.Flags().GetString("port")
 		healthCheckPath, _ := cmd.Flags().GetString("health_check_path")
@@ -49,15 +40,21 @@ var createTargetgroupCmd = &cobra.Command{
 			HealthyThreshold:    healthyThreshold,
 			UnhealthyThreshold:  unhealthyThreshold,
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		targetgroup, err := client.TargetGroup().Create(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Targetgroup Name", "Targetgroup Id", "Status")
-		tbl.AddRow(args[0], targetgroup.ID, targetgroup.Status)
-		tbl.Print()
+		return printResult(cmd, targetgroup, "ID", "Status")
 	},
 }
 
@@ -66,21 +63,17 @@ var getTargetgroupCmd = &cobra.Command{
 	Short:   "Get targetgroup info",
 	Example: "uthoctl targetgroup get <targetgroup-id>",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		targetgroup, err := client.TargetGroup().Read(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name", "Port", "Protocol", "HealthCheckPath")
-		tbl.AddRow(targetgroup.ID, targetgroup.Name, targetgroup.Port, targetgroup.Protocol, targetgroup.HealthCheckPath)
-		tbl.Print()
+		return printResult(cmd, targetgroup, "ID", "Name", "Port", "Protocol", "HealthCheckPath")
 	},
 }
 
@@ -88,52 +81,46 @@ var listTargetgroupCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List targetgroup info",
 	Example: "uthoctl targetgroup list",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		targetgroups, err := client.TargetGroup().List()
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Name", "Port", "Protocol", "HealthCheckPath")
-		for _, targetgroup := range targetgroups {
-			tbl.AddRow(targetgroup.ID, targetgroup.Name, targetgroup.Port, targetgroup.Protocol, targetgroup.HealthCheckPath)
-		}
-		tbl.Print()
+		return printResult(cmd, targetgroups, "ID", "Name", "Port", "Protocol", "HealthCheckPath")
 	},
 }
 
 var deleteTargetgroupCmd = &cobra.Command{
 	Use:     "delete",
 	Short:   "delete a targetgroup from your account.",
-	Example: "uthoctl targetgroup delete <targetgroup-id> <targetgroup-name>",
+	Example: "uthoctl targetgroup delete <targetgroup-id> <targetgroup-name>\nuthoctl targetgroup delete --selector 'name=test-*'",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ID: args[0], Name: args[1]})
+		}
+
+		if err := helper.Confirm(); err != nil {
+			return err
 		}
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		targetgroup, err := client.TargetGroup().Delete(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + targetgroup.Status)
+		return printResult(cmd, targetgroup, "Status")
 	},
 }
 
@@ -141,8 +128,8 @@ var deleteTargetgroupCmd = &cobra.Command{
 var targetgroupTargetCmd = &cobra.Command{
 	Use:   "target",
 	Short: "Use this command to manage targetgroup Security Group.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -151,13 +138,7 @@ var createTargetgroupTargetCmd = &cobra.Command{
 	Short:   "Create a targetgroup target.",
 	Example: "uthoctl targetgroup target create <targetgroup-id>",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
+	RunE: func(cmd *cobra.Command, args []string) error {
 		backendProtocol, _ := cmd.Flags().GetString("backend_protocol")
 		backendPort, _ := cmd.Flags().GetString("backend_port")
 		ip, _ := cmd.Flags().GetString("ip")
@@ -170,63 +151,60 @@ var createTargetgroupTargetCmd = &cobra.Command{
 			IP:              ip,
 			Cloudid:         cloudid,
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		target, err := client.TargetGroup().CreateTarget(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Target Name", "Target Id", "Status")
-		tbl.AddRow(args[1], target.ID, target.Status)
-		tbl.Print()
+		return printResult(cmd, target, "ID", "Status")
 	},
 }
 
 var getTargetgroupTargetCmd = &cobra.Command{
 	Use:     "get",
 	Short:   "Get targetgroup target info",
-	Example: "uthoctl targetgroup target get <targetgroup-id> <target-id",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	Example: "uthoctl targetgroup target get <targetgroup-id> <target-id>",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		target, err := client.TargetGroup().ReadTarget(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("IP", "Cloudid", "Status", "ID")
-		tbl.AddRow(target.IP, target.Cloudid, target.Status, target.ID)
-		tbl.Print()
+		return printResult(cmd, target, "IP", "Cloudid", "Status", "ID")
 	},
 }
 
 var listTargetgroupTargetCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List targetgroup policy",
-	Example: "uthoctl targetgroup policy list <targetgroup-id>",
-	Run: func(cmd *cobra.Command, args []string) {
+	Example: "uthoctl targetgroup target list <targetgroup-id>",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		targets, err := client.TargetGroup().ListTargets(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("IP", "Cloudid", "Status", "ID")
-		for _, target := range targets {
-			tbl.AddRow(target.IP, target.Cloudid, target.Status, target.ID)
-		}
-		tbl.Print()
+		return printResult(cmd, targets, "IP", "Cloudid", "Status", "ID")
 	},
 }
 
@@ -235,26 +213,26 @@ var deleteTargetgroupTargetCmd = &cobra.Command{
 	Short:   "delete a targetgroup Target from your account.",
 	Example: "uthoctl targetgroup target delete <targetgroup-id> <target-id>",
 	Args:    cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ParentID: args[0], ID: args[1]})
+		}
+
+		if err := helper.Confirm(); err != nil {
+			return err
 		}
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
 		target, err := client.TargetGroup().DeleteTarget(args[0], args[1])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + target.Status)
+		return printResult(cmd, target, "Status")
 	},
 }
 
@@ -270,20 +248,36 @@ func init() {
 	createTargetgroupCmd.Flags().String("health_check_timeout", "", "")
 	createTargetgroupCmd.Flags().String("healthy_threshold", "", "")
 	createTargetgroupCmd.Flags().String("unhealthy_threshold", "", "")
+	createTargetgroupCmd.MarkFlagRequired("protocol")
+	createTargetgroupCmd.MarkFlagRequired("port")
 
 	targetgroupCmd.AddCommand(getTargetgroupCmd)
+	resolveNames(getTargetgroupCmd, helper.KindTargetGroup)
 	targetgroupCmd.AddCommand(listTargetgroupCmd)
 	targetgroupCmd.AddCommand(deleteTargetgroupCmd)
+	resolveNames(deleteTargetgroupCmd, helper.KindTargetGroup)
+	addSelector(deleteTargetgroupCmd, targetgroupSelection, "delete", func(client utho.Client, c candidate) error {
+		_, err := client.TargetGroup().Delete(c.ID, c.Name)
+		return err
+	})
 
 	// TargetgroupTarget
 	targetgroupCmd.AddCommand(targetgroupTargetCmd)
 	targetgroupTargetCmd.AddCommand(createTargetgroupTargetCmd)
+	resolveNames(createTargetgroupTargetCmd, helper.KindTargetGroup)
 	createTargetgroupTargetCmd.Flags().String("backend_protocol", "", "")
 	createTargetgroupTargetCmd.Flags().String("backend_port", "", "")
 	createTargetgroupTargetCmd.Flags().String("ip", "", "")
 	createTargetgroupTargetCmd.Flags().String("cloudid", "", "")
+	resolveFlagNames(createTargetgroupTargetCmd, "cloudid", helper.KindInstance)
+	createTargetgroupTargetCmd.MarkFlagRequired("backend_protocol")
+	createTargetgroupTargetCmd.MarkFlagRequired("backend_port")
+	createTargetgroupTargetCmd.MarkFlagRequired("ip")
 
 	targetgroupTargetCmd.AddCommand(getTargetgroupTargetCmd)
+	resolveNames(getTargetgroupTargetCmd, helper.KindTargetGroup)
 	targetgroupTargetCmd.AddCommand(listTargetgroupTargetCmd)
+	resolveNames(listTargetgroupTargetCmd, helper.KindTargetGroup)
 	targetgroupTargetCmd.AddCommand(deleteTargetgroupTargetCmd)
+	resolveNames(deleteTargetgroupTargetCmd, helper.KindTargetGroup)
 }
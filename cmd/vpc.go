@@ -1,10 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-
-	"github.com/rodaine/table"
 	"github.com/spf13/cobra"
 	"github.com/uthoplatforms/utho-cli/helper"
 	"github.com/uthoplatforms/utho-go/utho"
@@ -13,8 +9,8 @@ import (
 var vpcCmd = &cobra.Command{
 	Use:   "vpc",
 	Short: "Use this command to manage VPCs.",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -23,12 +19,7 @@ var createVpcCmd = &cobra.Command{
 	Short:   "Create a vpc.",
 	Example: "uthoctl vpc create <vpc-name>",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
+	RunE: func(cmd *cobra.Command, args []string) error {
 		dcslug, _ := cmd.Flags().GetString("dcslug")
 		planid, _ := cmd.Flags().GetString("planid")
 		size, _ := cmd.Flags().GetString("size")
@@ -41,15 +32,24 @@ var createVpcCmd = &cobra.Command{
 			Network: network,
 			Size:    size,
 		}
+		if dryRun() {
+			return printDryRun(cmd, params)
+		}
+
+		client, err := helper.NewUthoClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
 		vpc, err := client.Vpc().Create(params)
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("Vpc Name", "Vpc Id", "Status")
-		tbl.AddRow(args[0], vpc.ID, vpc.Status)
-		tbl.Print()
+		if err := printResult(cmd, vpc, "ID", "Status"); err != nil {
+			return err
+		}
+		return waitFor(cmd, "VPC "+vpc.ID, waitReady, vpcStatus(vpc.ID))
 	},
 }
 
@@ -58,21 +58,17 @@ var getVpcCmd = &cobra.Command{
 	Short:   "Get vpc info",
 	Example: "uthoctl vpc get <vpc-id>",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := helper.NewUthoClient()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		vpc, err := client.Vpc().Read(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Network", "Name", "Size", "Dcslug")
-		tbl.AddRow(vpc.ID, vpc.Network, vpc.Name, vpc.Size, vpc.Dcslug)
-		tbl.Print()
+		return printResult(cmd, vpc, "ID", "Network", "Name", "Size", "Dcslug")
 	},
 }
 
@@ -80,65 +76,93 @@ var listVpcCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List vpc info",
 	Example: "uthoctl vpc list",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 		vpcs, err := client.Vpc().List()
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		tbl := table.New("ID", "Network", "Name", "Size", "Dcslug")
-		for _, vpc := range vpcs {
-			tbl.AddRow(vpc.ID, vpc.Network, vpc.Name, vpc.Size, vpc.Dcslug)
-		}
-		tbl.Print()
+		return printResult(cmd, vpcs, "ID", "Network", "Name", "Size", "Dcslug")
 	},
 }
 
 var deleteVpcCmd = &cobra.Command{
 	Use:     "delete",
 	Short:   "delete a vpc from your account.",
-	Example: "uthoctl vpc delete <vpc-id>",
+	Example: "uthoctl vpc delete <vpc-id>\nuthoctl vpc delete --selector 'name=test-*,dcslug=innoida'",
 	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		confirm := helper.Ask()
-		if !confirm {
-			fmt.Println("Operation aborted.")
-			os.Exit(1)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun() {
+			return printDryRun(cmd, dryRunRequest{ID: args[0]})
 		}
 
-		client, err := helper.NewUthoClient()
+		client, err := helper.NewUthoClient(cmd.Context())
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
+		}
+		if err := helper.ConfirmDelete("VPC", args[0], func() string {
+			vpc, err := client.Vpc().Read(args[0])
+			if err != nil {
+				return ""
+			}
+			return vpc.Name
+		}); err != nil {
+			return err
 		}
 
 		vpc, err := client.Vpc().Delete(args[0])
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			return err
 		}
 
-		fmt.Println("Status: " + vpc.Status)
+		if err := printResult(cmd, vpc, "Status"); err != nil {
+			return err
+		}
+		return waitFor(cmd, "VPC "+args[0], waitGone, vpcStatus(args[0]))
 	},
 }
 
+// vpcStatus reads the status of the VPC id, for --wait.
+func vpcStatus(id string) statusReader {
+	return func(client utho.Client) (string, error) {
+		vpc, err := client.Vpc().Read(id)
+		if err != nil {
+			return "", err
+		}
+		return vpc.Status, nil
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(vpcCmd)
 	// Vpc
 	vpcCmd.AddCommand(createVpcCmd)
+	addWaitFlags(createVpcCmd, "VPC is active")
 	createVpcCmd.Flags().String("dcslug", "", "Provide Zone dcslug eg: innoida")
-	createVpcCmd.Flags().String("Billing", "", "")
-	createVpcCmd.Flags().String("Size", "", "")
-	createVpcCmd.Flags().String("Price", "", "")
+	createVpcCmd.Flags().String("planid", "", "VPC plan ID")
+	createVpcCmd.Flags().String("network", "", "Network address of the VPC eg: 10.210.100.0")
+	createVpcCmd.Flags().String("size", "", "Network size of the VPC eg: 24")
+	renamedFlag(createVpcCmd, "Size", "size")
+	ignoredFlag(createVpcCmd, "Billing", "it was never sent to the API")
+	ignoredFlag(createVpcCmd, "Price", "it was never sent to the API")
+	createVpcCmd.MarkFlagRequired("dcslug")
+	createVpcCmd.MarkFlagRequired("planid")
+	createVpcCmd.MarkFlagRequired("network")
+	createVpcCmd.MarkFlagRequired("size")
 
 	vpcCmd.AddCommand(getVpcCmd)
+	resolveNames(getVpcCmd, helper.KindVPC)
 	vpcCmd.AddCommand(listVpcCmd)
 	vpcCmd.AddCommand(deleteVpcCmd)
+	resolveNames(deleteVpcCmd, helper.KindVPC)
+	addWaitFlags(deleteVpcCmd, "VPC is deleted")
+	addSelector(deleteVpcCmd, vpcSelection, "delete", func(client utho.Client, c candidate) error {
+		_, err := client.Vpc().Delete(c.ID)
+		return err
+	})
 }
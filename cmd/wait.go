@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/uthoplatforms/utho-cli/helper"
+	"github.com/uthoplatforms/utho-go/utho"
+)
+
+// statusReader reads the status of a resource.
+type statusReader func(client utho.Client) (string, error)
+
+// Goals of waitFor.
+const (
+	waitReady = iota // the resource is in a ready state
+	waitGone         // reading the resource fails with a not found error
+)
+
+// addWaitFlags adds --wait, --wait-timeout and --poll-interval to cmd. until
+// completes the help of --wait, eg: "instance is active".
+func addWaitFlags(cmd *cobra.Command, until string) {
+	cmd.Flags().Bool("wait", false, "Wait until the "+until)
+	addPollFlags(cmd)
+}
+
+// addPollFlags adds --wait-timeout and --poll-interval to cmd.
+func addPollFlags(cmd *cobra.Command) {
+	cmd.Flags().Duration("wait-timeout", helper.DefaultWaitTimeout, "Give up waiting after this long")
+	cmd.Flags().Duration("poll-interval", helper.DefaultPollInterval, "Time between two checks while waiting")
+}
+
+// waitOptions returns the options set with --wait-timeout and
+// --poll-interval.
+func waitOptions(cmd *cobra.Command) helper.WaitOptions {
+	var opts helper.WaitOptions
+	opts.Timeout, _ = cmd.Flags().GetDuration("wait-timeout")
+	opts.Interval, _ = cmd.Flags().GetDuration("poll-interval")
+	return opts
+}
+
+// checkWaitFlags rejects the wait flags of cmd, if it has some, that would
+// only fail once the resource is created.
+func checkWaitFlags(cmd *cobra.Command) error {
+	if cmd.Flags().Lookup("poll-interval") == nil {
+		return nil
+	}
+	if interval, _ := cmd.Flags().GetDuration("poll-interval"); interval <= 0 {
+		return fmt.Errorf("invalid poll interval %s", interval)
+	}
+	if timeout, _ := cmd.Flags().GetDuration("wait-timeout"); timeout < 0 {
+		return fmt.Errorf("invalid wait timeout %s", timeout)
+	}
+	return nil
+}
+
+// waitFor polls the resource with read until it reaches goal, when --wait is
+// set. what names the resource in messages, eg: "instance 1234".
+func waitFor(cmd *cobra.Command, what string, goal int, read statusReader) error {
+	if wait, _ := cmd.Flags().GetBool("wait"); !wait {
+		return nil
+	}
+	var client utho.Client
+	return helper.Wait(cmd.Context(), waitOptions(cmd), what, func(ctx context.Context) (string, helper.WaitState, error) {
+		if client == nil {
+			c, err := helper.NewUthoClient(ctx)
+			if err != nil {
+				return "", helper.WaitPending, err
+			}
+			client = c
+		}
+
+		status, err := read(client)
+		switch {
+		case goal == waitGone && helper.ExitCode(err) == helper.ExitNotFound:
+			return "deleted", helper.WaitDone, nil
+		case err != nil:
+			return "", helper.WaitPending, err
+		case goal == waitGone:
+			return status, helper.WaitPending, nil
+		default:
+			return status, helper.StatusState(status), nil
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// actionKeys are the response fields carrying the ID of the action a request
+// triggered, for the endpoints that report it.
+var actionKeys = []string{"actionid", "action_id"}
+
+// resourceKeys are the response fields carrying the ID of a created
+// resource.
+var resourceKeys = []string{"id", "cloudid", "firewallid", "loadbalancerid", "clusterid", "vpcid"}
+
+var triggered struct {
+	sync.Mutex
+	ids       []string
+	resources []string
+}
+
+// TriggeredActions returns the IDs of the actions reported by the API for the
+// requests sent since the last ResetTriggeredActions.
+func TriggeredActions() []string {
+	triggered.Lock()
+	defer triggered.Unlock()
+	return append([]string(nil), triggered.ids...)
+}
+
+// CreatedResources returns the IDs of the resources reported by the API for
+// the requests sent since the last ResetTriggeredActions.
+func CreatedResources() []string {
+	triggered.Lock()
+	defer triggered.Unlock()
+	return append([]string(nil), triggered.resources...)
+}
+
+// ResetTriggeredActions forgets the actions and resources seen so far.
+func ResetTriggeredActions() {
+	triggered.Lock()
+	defer triggered.Unlock()
+	triggered.ids = nil
+	triggered.resources = nil
+}
+
+// actionTransport notes the action and resource IDs found in the responses
+// to mutating requests sent through next.
+type actionTransport struct {
+	next http.RoundTripper
+}
+
+func (t *actionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || req.Method == http.MethodGet || resp.StatusCode >= 300 {
+		return resp, err
+	}
+	body, err := peekBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]any
+	if json.Unmarshal(body, &fields) != nil {
+		return resp, nil
+	}
+	triggered.Lock()
+	defer triggered.Unlock()
+	triggered.ids = appendIDs(triggered.ids, fields, actionKeys)
+	triggered.resources = appendIDs(triggered.resources, fields, resourceKeys)
+	return resp, nil
+}
+
+// appendIDs appends to ids the non empty values of the keys of fields.
+func appendIDs(ids []string, fields map[string]any, keys []string) []string {
+	for _, key := range keys {
+		switch id := fields[key].(type) {
+		case string:
+			if id != "" {
+				ids = append(ids, id)
+			}
+		case float64:
+			ids = append(ids, fmt.Sprint(id))
+		}
+	}
+	return ids
+}
+
+// ActionTimeLayout is the format of the StartedAt and CompletedAt fields of
+// actions.
+const ActionTimeLayout = "2006-01-02 15:04:05"
+
+// ActionCompleted reports whether completedAt, the CompletedAt field of an
+// action, is set. Pending actions leave it empty or zero.
+func ActionCompleted(completedAt string) bool {
+	completedAt = strings.TrimSpace(completedAt)
+	return completedAt != "" && !strings.HasPrefix(completedAt, "0000-00-00")
+}
+
+// ParseSince parses the value of a --since flag, either a duration before
+// now such as 1h or a time such as 2024-01-31 or "2024-01-31 15:04:05".
+// Times are read in the local time zone, like the action timestamps.
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("invalid --since %q: duration must be positive", value)
+		}
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{ActionTimeLayout, time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q, use a duration such as 1h or a time such as 2024-01-31 or \"2024-01-31 15:04:05\"", value)
+}
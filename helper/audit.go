@@ -0,0 +1,188 @@
+package helper
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// AuditOff disables the audit log when given as its location.
+const AuditOff = "off"
+
+// Status of an audit entry.
+const (
+	AuditOK      = "ok"
+	AuditAborted = "aborted"
+	AuditFailed  = "failed"
+)
+
+// AuditEntry is a line of the audit log, written for every run of a
+// mutating command.
+type AuditEntry struct {
+	Time    string `json:"time"`
+	User    string `json:"user"`
+	Host    string `json:"host"`
+	Context string `json:"context"`
+	Command string `json:"command"`
+	// Args and Flags are the positional arguments and the flags set on the
+	// command line, with secrets redacted.
+	Args  []string          `json:"args,omitempty"`
+	Flags map[string]string `json:"flags,omitempty"`
+	// ResourceIDs are the IDs the API reported for the created resources,
+	// and those of the resources selected with --selector.
+	ResourceIDs []string `json:"resource_ids,omitempty"`
+	// Results are the outcomes of the command on each selected resource.
+	Results  []AuditResult `json:"results,omitempty"`
+	Status   string        `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	ExitCode int           `json:"exit_code"`
+}
+
+// AuditResult is the outcome of a command on one of the resources it
+// selected, eg: "success" or "failed" with the error.
+type AuditResult struct {
+	ID     string `json:"id"`
+	Name   string `json:"name,omitempty"`
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// secretFlags matches the names of flags whose values are not logged.
+var secretFlags = regexp.MustCompile(`(?i)token|password|secret|auth`)
+
+// NewAuditEntry describes the run of command, eg: "instance delete", that
+// ended with err. flags are the flags set on the command line.
+func NewAuditEntry(command string, args []string, flags map[string]string, err error) AuditEntry {
+	entry := AuditEntry{
+		Time:        time.Now().Format(time.RFC3339),
+		Context:     DefaultContext,
+		Command:     command,
+		Args:        args,
+		ResourceIDs: CreatedResources(),
+		Status:      AuditOK,
+		ExitCode:    ExitCode(err),
+	}
+	if u, err := user.Current(); err == nil {
+		entry.User = u.Username
+	}
+	entry.Host, _ = os.Hostname()
+	if cfg, err := LoadConfig(); err == nil {
+		entry.Context = cfg.ContextName()
+	}
+	for name, value := range flags {
+		if entry.Flags == nil {
+			entry.Flags = map[string]string{}
+		}
+		if secretFlags.MatchString(name) {
+			value = redacted
+		}
+		entry.Flags[name] = value
+	}
+	if err != nil {
+		entry.Status, entry.Error = AuditFailed, err.Error()
+		if errors.Is(err, ErrAborted) || errors.Is(err, ErrNotInteractive) {
+			entry.Status = AuditAborted
+		}
+	}
+	return entry
+}
+
+// AuditLogFile returns the path of the audit log: UTHO_AUDIT_LOG, then the
+// audit-log setting of the config file, then uthoctl/audit.jsonl in the XDG
+// state directory (~/.local/state by default). It returns "" when the log
+// is off.
+func AuditLogFile() (string, error) {
+	file := viper.GetString("audit_log")
+	if file == "" {
+		cfg, err := LoadConfig()
+		if err != nil {
+			return "", err
+		}
+		file = cfg.AuditLog
+	}
+	switch file {
+	case AuditOff:
+		return "", nil
+	case "":
+	default:
+		return file, nil
+	}
+
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("error finding home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "uthoctl", "audit.jsonl"), nil
+}
+
+// AppendAudit adds entry at the end of the audit log, which is only
+// readable by the current user.
+func AppendAudit(entry AuditEntry) error {
+	file, err := AuditLogFile()
+	if err != nil || file == "" {
+		return err
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadAuditLog returns the entries of the audit log, oldest first, whose
+// line matches match, or all of them when match is nil. A missing log is
+// empty.
+func ReadAuditLog(match func(line string) bool) ([]AuditEntry, error) {
+	entries := []AuditEntry{}
+	file, err := AuditLogFile()
+	if err != nil || file == "" {
+		return entries, err
+	}
+	f, err := os.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if line == "" || (match != nil && !match(line)) {
+			continue
+		}
+		var entry AuditEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("invalid audit log entry at %s:%d: %w", file, n, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,248 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// ErrNotRecorded is returned in replay mode for a request with no recorded
+// exchange left in the cassette.
+var ErrNotRecorded = errors.New("no recorded response")
+
+// redacted replaces the token in recorded exchanges.
+const redacted = "REDACTED"
+
+// CassetteDirs returns the directories set with --record and --replay. At
+// most one of them is set.
+func CassetteDirs() (record, replay string) {
+	return viper.GetString("record"), viper.GetString("replay")
+}
+
+// Replaying reports whether responses are served from a cassette.
+func Replaying() bool {
+	_, replay := CassetteDirs()
+	return replay != ""
+}
+
+// exchange is one recorded request and its response. A cassette is a
+// directory holding an exchange per file, named after its position.
+type exchange struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type recordedResponse struct {
+	Status     string      `json:"status"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// recordingTransport saves every exchange made through next in dir. The
+// token is replaced wherever it appears, and the secret fields of the
+// bodies are redacted as in the --debug trace.
+type recordingTransport struct {
+	next http.RoundTripper
+	dir  string
+
+	mu sync.Mutex
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := peekBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := peekBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	scrub := tokenScrubber(req.Header)
+	ex := exchange{
+		Request: recordedRequest{
+			Method: req.Method,
+			URL:    scrub(req.URL.String()),
+			Header: scrubHeader(req.Header, scrub),
+			Body:   scrub(string(redactBody(reqBody))),
+		},
+		Response: recordedResponse{
+			Status:     resp.Status,
+			StatusCode: resp.StatusCode,
+			Header:     scrubHeader(resp.Header, scrub),
+			Body:       scrub(string(redactBody(respBody))),
+		},
+	}
+	if err := t.save(ex); err != nil {
+		return nil, fmt.Errorf("cannot record %s %s: %w", req.Method, req.URL, err)
+	}
+	return resp, nil
+}
+
+// save writes ex after the exchanges already in the cassette, so several
+// commands can be recorded in the same directory.
+func (t *recordingTransport) save(ex exchange) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return err
+	}
+	files, err := cassetteFiles(t.dir)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(ex, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%04d-%s.json", len(files)+1, exchangeName(ex.Request))
+	return os.WriteFile(filepath.Join(t.dir, name), append(data, '\n'), 0o600)
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// exchangeName describes a request in a file name, eg: GET-v2-cloud.
+func exchangeName(req recordedRequest) string {
+	path, _, _ := strings.Cut(requestURI(req.URL), "?")
+	name := strings.Trim(unsafeChars.ReplaceAllString(path, "-"), "-")
+	if len(name) > 60 {
+		name = name[:60]
+	}
+	return req.Method + "-" + name
+}
+
+// tokenScrubber returns a function replacing the bearer token of header.
+func tokenScrubber(header http.Header) func(string) string {
+	token := strings.TrimSpace(strings.TrimPrefix(header.Get("Authorization"), "Bearer"))
+	return func(s string) string {
+		if token == "" {
+			return s
+		}
+		return strings.ReplaceAll(s, token, redacted)
+	}
+}
+
+func scrubHeader(header http.Header, scrub func(string) string) http.Header {
+	out := make(http.Header, len(header))
+	for name, values := range header {
+		for _, v := range values {
+			if strings.EqualFold(name, "Authorization") {
+				v = redacted
+			}
+			out.Add(name, scrub(v))
+		}
+	}
+	return out
+}
+
+// replayTransport answers requests from a cassette without any network
+// access. A request gets the first unused exchange with the same method,
+// path and query; the host is ignored so a cassette recorded against any
+// --api-url can be replayed.
+type replayTransport struct {
+	mu        sync.Mutex
+	exchanges []exchange
+	used      []bool
+}
+
+func newReplayTransport(dir string) (*replayTransport, error) {
+	files, err := cassetteFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read cassette: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("cassette %s is empty", dir)
+	}
+
+	t := &replayTransport{used: make([]bool, len(files))}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var ex exchange
+		if err := json.Unmarshal(data, &ex); err != nil {
+			return nil, fmt.Errorf("invalid cassette file %s: %w", file, err)
+		}
+		t.exchanges = append(t.exchanges, ex)
+	}
+	return t, nil
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	key := requestKey(req.Method, req.URL.String())
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, ex := range t.exchanges {
+		if t.used[i] || requestKey(ex.Request.Method, ex.Request.URL) != key {
+			continue
+		}
+		t.used[i] = true
+		return &http.Response{
+			Status:        ex.Response.Status,
+			StatusCode:    ex.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        ex.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader([]byte(ex.Response.Body))),
+			ContentLength: int64(len(ex.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w for %s %s", ErrNotRecorded, req.Method, req.URL.RequestURI())
+}
+
+// requestKey is the method, path and query of a request.
+func requestKey(method, rawURL string) string {
+	return method + " " + requestURI(rawURL)
+}
+
+// requestURI returns the path and query of rawURL.
+func requestURI(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.RequestURI()
+}
+
+// cassetteFiles returns the exchange files of dir in recording order. A
+// missing directory is an empty cassette.
+func cassetteFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "[0-9]*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,396 @@
+package helper
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultContext is the context used when none has been created yet. Tokens
+// saved by older versions in the top-level token key are moved there.
+const DefaultContext = "default"
+
+// Config is the content of ~/.config/uthoctl.yaml.
+type Config struct {
+	CurrentContext string              `yaml:"current-context,omitempty"`
+	Contexts       map[string]*Context `yaml:"contexts,omitempty"`
+
+	// Store selects where tokens are kept: plaintext (in this file, the
+	// default), file (encrypted with a passphrase) or helper (an external
+	// program named by Helper).
+	Store  string `yaml:"credential-store,omitempty"`
+	Helper string `yaml:"credential-helper,omitempty"`
+
+	// AuditLog is the file mutating commands are logged to, "off" to
+	// disable the log. See AuditLogFile.
+	AuditLog string `yaml:"audit-log,omitempty"`
+
+	// Token is only read, to migrate files written by older versions.
+	Token string `yaml:"token,omitempty"`
+}
+
+// Context holds the settings of one Utho account.
+type Context struct {
+	// Token is only set with the plaintext credential store.
+	Token string `yaml:"token,omitempty"`
+	// APIURL overrides the Utho API endpoint, eg: a regional endpoint.
+	APIURL string `yaml:"api_url,omitempty"`
+	// Retries and RetryMutating set how failed requests are retried.
+	Retries       *int `yaml:"retries,omitempty"`
+	RetryMutating bool `yaml:"retry_mutating,omitempty"`
+	// Confirm is how deletes are confirmed: prompt (y/n, the default) or
+	// typed (the name or ID of the resource must be typed back).
+	Confirm string `yaml:"confirm,omitempty"`
+	// Defaults are flag values applied to every command run in this
+	// context, eg: dcslug: innoida.
+	Defaults map[string]string `yaml:"defaults,omitempty"`
+}
+
+// ConfigFile returns the path of the configuration file: --config, then
+// UTHO_CONFIG, then ~/.config/uthoctl.yaml.
+func ConfigFile() (string, error) {
+	if file := viper.GetString("config"); file != "" {
+		return file, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error finding home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "uthoctl.yaml"), nil
+}
+
+// LoadConfig reads the configuration file. A missing file is not an error
+// and returns an empty configuration.
+func LoadConfig() (*Config, error) {
+	file, err := ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("error reading config file %s: %w", file, err)
+	}
+
+	if cfg.Token != "" {
+		if cfg.Contexts[DefaultContext] == nil {
+			cfg.SetContext(DefaultContext, &Context{Token: cfg.Token})
+		}
+		if cfg.CurrentContext == "" {
+			cfg.CurrentContext = DefaultContext
+		}
+		cfg.Token = ""
+	}
+	return cfg, nil
+}
+
+// Save writes the configuration file, readable by the current user only.
+func (c *Config) Save() error {
+	file, err := ConfigFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return fmt.Errorf("error creating config directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("error writing config file: %w", err)
+	}
+	return nil
+}
+
+// ContextName returns the name of the context selected with --context, or
+// the current one.
+func (c *Config) ContextName() string {
+	if name := viper.GetString("context"); name != "" {
+		return name
+	}
+	if c.CurrentContext != "" {
+		return c.CurrentContext
+	}
+	return DefaultContext
+}
+
+// Context returns the active context, or nil if it does not exist yet.
+func (c *Config) Context() *Context {
+	return c.Contexts[c.ContextName()]
+}
+
+// ContextSettings are the keys of the settings of a context, the others
+// Context.Set accepts are flag defaults.
+var ContextSettings = []string{"api_url", "retries", "retry_mutating", "confirm"}
+
+// Set changes a setting of the context. Keys other than ContextSettings are
+// flag defaults.
+func (c *Context) Set(key, value string) error {
+	switch key {
+	case "api_url":
+		if value != "" {
+			if err := validateAPIURL(value); err != nil {
+				return err
+			}
+		}
+		c.APIURL = value
+	case "retries":
+		c.Retries = nil
+		if value != "" {
+			retries, err := strconv.Atoi(value)
+			if err != nil || retries < 0 {
+				return UsageError(fmt.Errorf("invalid number of retries %q", value))
+			}
+			c.Retries = &retries
+		}
+	case "retry_mutating":
+		c.RetryMutating = false
+		if value != "" {
+			mutating, err := StringToBool(value)
+			if err != nil {
+				return UsageError(err)
+			}
+			c.RetryMutating = mutating
+		}
+	case "confirm":
+		if value != "" && value != ConfirmPrompt && value != ConfirmTyped {
+			return UsageError(fmt.Errorf("invalid confirm mode %q, use %s or %s", value, ConfirmPrompt, ConfirmTyped))
+		}
+		c.Confirm = value
+	default:
+		if c.Defaults == nil {
+			c.Defaults = map[string]string{}
+		}
+		if value == "" {
+			delete(c.Defaults, key)
+		} else {
+			c.Defaults[key] = value
+		}
+	}
+	return nil
+}
+
+// SetContext adds or replaces the named context.
+func (c *Config) SetContext(name string, ctx *Context) {
+	if c.Contexts == nil {
+		c.Contexts = map[string]*Context{}
+	}
+	c.Contexts[name] = ctx
+}
+
+// RenameContext renames a context and moves its token.
+func (c *Config) RenameContext(from, to string) error {
+	ctx, ok := c.Contexts[from]
+	if !ok {
+		return fmt.Errorf("context %q not found", from)
+	}
+	if _, ok := c.Contexts[to]; ok {
+		return fmt.Errorf("context %q already exists", to)
+	}
+
+	store, err := c.CredentialStore()
+	if err != nil {
+		return err
+	}
+	token, err := store.Get(from)
+	if err != nil && !errors.Is(err, ErrCredentialsNotFound) {
+		return err
+	}
+
+	c.Contexts[to] = ctx
+	delete(c.Contexts, from)
+	if c.CurrentContext == from {
+		c.CurrentContext = to
+	}
+	if token == "" {
+		return nil
+	}
+	if err := store.Store(to, token); err != nil {
+		return err
+	}
+	return store.Erase(from)
+}
+
+// DeleteContext removes a context and its token.
+func (c *Config) DeleteContext(name string) error {
+	if _, ok := c.Contexts[name]; !ok {
+		return fmt.Errorf("context %q not found", name)
+	}
+
+	store, err := c.CredentialStore()
+	if err != nil {
+		return err
+	}
+	if err := store.Erase(name); err != nil {
+		return err
+	}
+
+	delete(c.Contexts, name)
+	if c.CurrentContext == name {
+		c.CurrentContext = ""
+	}
+	return nil
+}
+
+// MigrateCredentials moves the tokens of every context to another credential
+// store and returns how many were moved. Moving them to the store already in
+// use is an error, as erasing them from the old store would lose them. The
+// configuration is saved before the tokens are erased from the old store, so
+// that they are still found there if saving fails.
+func (c *Config) MigrateCredentials(store, helper string) (int, error) {
+	if store == "" {
+		store = StorePlaintext
+	}
+	current := c.Store
+	if current == "" {
+		current = StorePlaintext
+	}
+	if store == current && (store != StoreHelper || helper == c.Helper) {
+		return 0, UsageError(fmt.Errorf("tokens are already kept in the %s credential store", store))
+	}
+
+	from, err := c.CredentialStore()
+	if err != nil {
+		return 0, err
+	}
+	to, err := c.credentialStore(store, helper)
+	if err != nil {
+		return 0, err
+	}
+
+	var moved []string
+	for _, name := range c.ContextNames() {
+		token, err := from.Get(name)
+		if errors.Is(err, ErrCredentialsNotFound) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("reading token of context %q: %w", name, err)
+		}
+		if err := to.Store(name, token); err != nil {
+			return 0, fmt.Errorf("storing token of context %q: %w", name, err)
+		}
+		moved = append(moved, name)
+	}
+
+	oldStore, oldHelper := c.Store, c.Helper
+	c.Store, c.Helper = store, helper
+	if c.Store == StorePlaintext {
+		c.Store = ""
+	}
+	if err := c.Save(); err != nil {
+		c.Store, c.Helper = oldStore, oldHelper
+		return 0, err
+	}
+
+	for _, name := range moved {
+		if err := from.Erase(name); err != nil {
+			return len(moved), fmt.Errorf("erasing old token of context %q: %w", name, err)
+		}
+	}
+	// Tokens erased from the config file are only gone once it is saved.
+	if _, ok := from.(*plaintextStore); ok {
+		return len(moved), c.Save()
+	}
+	return len(moved), nil
+}
+
+// ContextNames returns the sorted names of all contexts.
+func (c *Config) ContextNames() []string {
+	names := make([]string, 0, len(c.Contexts))
+	for name := range c.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResolveToken returns the API token and a description of where it comes
+// from, in order of precedence: the --token flag, the UTHO_TOKEN environment
+// variable, then the active context of the configuration file.
+func ResolveToken() (token string, source string, err error) {
+	if token := viper.GetString("token"); token != "" {
+		return token, "--token flag", nil
+	}
+	if token := os.Getenv("UTHO_TOKEN"); token != "" {
+		return token, "UTHO_TOKEN environment variable", nil
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return "", "", err
+	}
+	file, _ := ConfigFile()
+	name := cfg.ContextName()
+	if cfg.Context() == nil && viper.GetString("context") != "" {
+		return "", "", UsageError(fmt.Errorf("context %q not found in %s", name, file))
+	}
+
+	store, err := cfg.CredentialStore()
+	if err != nil {
+		return "", "", err
+	}
+	token, err = store.Get(name)
+	if errors.Is(err, ErrCredentialsNotFound) {
+		return "", "", fmt.Errorf("%w for context %q in %s. Run 'uthoctl auth', set UTHO_TOKEN or pass --token", ErrNoToken, name, file)
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	source = fmt.Sprintf("context %q in %s", name, file)
+	if cfg.Store != "" && cfg.Store != StorePlaintext {
+		source = fmt.Sprintf("context %q, %s credential store", name, cfg.Store)
+	}
+	return token, source, nil
+}
+
+// APIURL returns the API endpoint to use, in order of precedence: the
+// --api-url flag, the UTHO_API_URL environment variable, then the api_url
+// of the active context. An empty string means the utho-go default.
+func APIURL() (string, error) {
+	apiURL := viper.GetString("api_url")
+	if apiURL == "" {
+		cfg, err := LoadConfig()
+		if err != nil {
+			return "", err
+		}
+		if ctx := cfg.Context(); ctx != nil {
+			apiURL = ctx.APIURL
+		}
+	}
+	if apiURL == "" {
+		return "", nil
+	}
+	if err := validateAPIURL(apiURL); err != nil {
+		return "", err
+	}
+	return apiURL, nil
+}
+
+func validateAPIURL(apiURL string) error {
+	u, err := url.Parse(apiURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return UsageError(fmt.Errorf("invalid API URL %q, expected eg: https://api.utho.com/v2/", apiURL))
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package helper
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+// Confirmation modes of a context, see Context.Confirm.
+const (
+	ConfirmPrompt = "prompt"
+	ConfirmTyped  = "typed"
+)
+
+// ErrNotInteractive is returned instead of prompting when stdin is not a
+// terminal and --yes is not set.
+var ErrNotInteractive = UsageError(errors.New("confirmation required but stdin is not a terminal, pass --yes to proceed"))
+
+// StdinIsTerminal reports whether Stdin is a terminal a user can answer
+// prompts on. Tests replace it.
+var StdinIsTerminal = func() bool {
+	f, ok := Stdin.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// Confirm asks on stderr whether to proceed with a destructive operation,
+// unless --yes is set. Anything but "y" declines with ErrAborted.
+func Confirm() error {
+	if Confirmed() {
+		return nil
+	}
+	return confirmYes("")
+}
+
+// confirmYes asks whether to proceed after message, declining with
+// ErrAborted on anything but "y".
+func confirmYes(message string) error {
+	answer, err := prompt(message + "Are you sure you want to proceed? (y/n): ")
+	if err != nil {
+		return err
+	}
+	if strings.ToLower(answer) != "y" {
+		return ErrAborted
+	}
+	return nil
+}
+
+// ConfirmDelete confirms the deletion of a resource, eg: what "instance" and
+// id "1234". With the typed confirmation mode of the active context, the name
+// or ID must be typed back instead of answering y. lookup finds the name for
+// that mode, it may be nil or return "" when unknown.
+func ConfirmDelete(what, id string, lookup func() string) error {
+	if Confirmed() {
+		return nil
+	}
+	if ConfirmMode() != ConfirmTyped {
+		return Confirm()
+	}
+
+	name := ""
+	if lookup != nil {
+		name = lookup()
+	}
+	label, hint := id, "ID"
+	if name != "" && name != id {
+		label, hint = fmt.Sprintf("%s (%s)", name, id), "name or ID"
+	}
+	answer, err := prompt(fmt.Sprintf("This will permanently delete %s %s.\nType its %s to confirm: ", what, label, hint))
+	if err != nil {
+		return err
+	}
+	if answer != id && (name == "" || answer != name) {
+		return fmt.Errorf("%w: %q does not match the %s of %s %s", ErrAborted, answer, hint, what, label)
+	}
+	return nil
+}
+
+// ConfirmBulk confirms an operation on several resources at once, eg: verb
+// "delete" and what "instance", after listing items, their names and IDs.
+// With the typed confirmation mode of the active context, their number must
+// be typed back instead of answering y.
+func ConfirmBulk(verb, what string, items []string) error {
+	if Confirmed() {
+		return nil
+	}
+	var list strings.Builder
+	if len(items) != 1 {
+		what += "s"
+	}
+	fmt.Fprintf(&list, "This will %s %d %s:\n", verb, len(items), what)
+	for _, item := range items {
+		fmt.Fprintf(&list, "  %s\n", item)
+	}
+	if ConfirmMode() != ConfirmTyped {
+		return confirmYes(list.String())
+	}
+
+	count := strconv.Itoa(len(items))
+	answer, err := prompt(list.String() + fmt.Sprintf("Type %s, the number of %s, to confirm: ", count, what))
+	if err != nil {
+		return err
+	}
+	if answer != count {
+		return fmt.Errorf("%w: %q is not the number of %s", ErrAborted, answer, what)
+	}
+	return nil
+}
+
+// ConfirmMode returns the confirmation mode of the active context.
+func ConfirmMode() string {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return ConfirmPrompt
+	}
+	if ctx := cfg.Context(); ctx != nil && ctx.Confirm != "" {
+		return ctx.Confirm
+	}
+	return ConfirmPrompt
+}
+
+// Confirmed reports whether prompts are skipped with --yes.
+func Confirmed() bool {
+	return viper.GetBool("yes")
+}
+
+// prompt writes question on stderr and returns the answer read from stdin,
+// or ErrNotInteractive when nobody can answer.
+func prompt(question string) (string, error) {
+	if !StdinIsTerminal() {
+		return "", ErrNotInteractive
+	}
+
+	fmt.Fprint(Stderr, question)
+	input, err := bufio.NewReader(Stdin).ReadString('\n')
+	if err != nil && input == "" {
+		fmt.Fprintln(Stderr)
+		return "", ErrAborted
+	}
+	return strings.TrimSpace(input), nil
+}
@@ -0,0 +1,362 @@
+package helper
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/term"
+)
+
+// Credential stores selectable with the credential-store config key.
+const (
+	StorePlaintext = "plaintext"
+	StoreFile      = "file"
+	StoreHelper    = "helper"
+)
+
+// CredentialStores lists the supported credential stores.
+var CredentialStores = []string{StorePlaintext, StoreFile, StoreHelper}
+
+// ErrCredentialsNotFound is returned by CredentialStore.Get when no token is
+// stored for the context.
+var ErrCredentialsNotFound = errors.New("credentials not found")
+
+// CredentialStore keeps the API token of each context.
+type CredentialStore interface {
+	Get(context string) (string, error)
+	Store(context, token string) error
+	Erase(context string) error
+}
+
+// CredentialStore returns the store selected in the configuration. The
+// plaintext store writes into c, which must be saved afterwards.
+func (c *Config) CredentialStore() (CredentialStore, error) {
+	return c.credentialStore(c.Store, c.Helper)
+}
+
+func (c *Config) credentialStore(store, helper string) (CredentialStore, error) {
+	switch store {
+	case "", StorePlaintext:
+		return &plaintextStore{cfg: c}, nil
+	case StoreFile:
+		file, err := ConfigFile()
+		if err != nil {
+			return nil, err
+		}
+		return &fileStore{path: filepath.Join(filepath.Dir(file), "uthoctl-credentials.enc")}, nil
+	case StoreHelper:
+		if helper == "" {
+			return nil, errors.New("credential-helper must name an executable when credential-store is helper")
+		}
+		return &helperStore{program: helper}, nil
+	default:
+		return nil, fmt.Errorf("unknown credential store %q (supported: %s)", store, strings.Join(CredentialStores, ", "))
+	}
+}
+
+// plaintextStore keeps tokens in the config file, as older versions did.
+type plaintextStore struct {
+	cfg *Config
+}
+
+func (s *plaintextStore) Get(context string) (string, error) {
+	ctx := s.cfg.Contexts[context]
+	if ctx == nil || ctx.Token == "" {
+		return "", ErrCredentialsNotFound
+	}
+	return ctx.Token, nil
+}
+
+func (s *plaintextStore) Store(context, token string) error {
+	ctx := s.cfg.Contexts[context]
+	if ctx == nil {
+		ctx = &Context{}
+		s.cfg.SetContext(context, ctx)
+	}
+	ctx.Token = token
+	return nil
+}
+
+func (s *plaintextStore) Erase(context string) error {
+	if ctx := s.cfg.Contexts[context]; ctx != nil {
+		ctx.Token = ""
+	}
+	return nil
+}
+
+// fileStore keeps tokens in a file encrypted with AES-256-GCM, using a key
+// derived from a passphrase read from UTHO_PASSPHRASE or the terminal.
+type fileStore struct {
+	path string
+}
+
+type encryptedFile struct {
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+const pbkdf2Iterations = 600000
+
+// passphrase is kept for the duration of the command once typed.
+var passphrase string
+
+// ReadPassword reads a line from Stdin without echoing it. Tests replace it.
+var ReadPassword = func() ([]byte, error) {
+	f, ok := Stdin.(*os.File)
+	if !ok {
+		return nil, errors.New("stdin is not a terminal")
+	}
+	return term.ReadPassword(int(f.Fd()))
+}
+
+// readPassphrase returns the passphrase of the credential file, asking for
+// it twice on a terminal when confirm is set, as when creating the file.
+func readPassphrase(confirm bool) (string, error) {
+	if passphrase != "" {
+		return passphrase, nil
+	}
+	if env := os.Getenv("UTHO_PASSPHRASE"); env != "" {
+		passphrase = env
+		return passphrase, nil
+	}
+	if !StdinIsTerminal() {
+		return "", errors.New("the credential file is encrypted: set UTHO_PASSPHRASE or run from a terminal")
+	}
+
+	fmt.Fprint(Stderr, "Enter the credential file passphrase: ")
+	b, err := ReadPassword()
+	fmt.Fprintln(Stderr)
+	if err != nil {
+		return "", err
+	}
+	if confirm {
+		fmt.Fprint(Stderr, "Confirm the passphrase: ")
+		again, err := ReadPassword()
+		fmt.Fprintln(Stderr)
+		if err != nil {
+			return "", err
+		}
+		if !bytes.Equal(b, again) {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	if len(b) == 0 {
+		return "", errors.New("empty passphrase")
+	}
+	passphrase = string(b)
+	return passphrase, nil
+}
+
+func (s *fileStore) load() (map[string]string, error) {
+	tokens := map[string]string{}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file encryptedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", s.path, err)
+	}
+	pass, err := readPassphrase(false)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(pass, file.Salt, file.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt %s: wrong passphrase?", s.path)
+	}
+	if err := json.Unmarshal(plain, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (s *fileStore) save(tokens map[string]string) error {
+	_, statErr := os.Stat(s.path)
+	pass, err := readPassphrase(errors.Is(statErr, os.ErrNotExist))
+	if err != nil {
+		return err
+	}
+
+	plain, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	file := encryptedFile{Iterations: pbkdf2Iterations, Salt: make([]byte, 16)}
+	if _, err := rand.Read(file.Salt); err != nil {
+		return err
+	}
+	gcm, err := newGCM(pass, file.Salt, file.Iterations)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return err
+	}
+	file.Data = gcm.Seal(nil, file.Nonce, plain, nil)
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o600)
+}
+
+func (s *fileStore) Get(context string) (string, error) {
+	if _, err := os.Stat(s.path); errors.Is(err, os.ErrNotExist) {
+		return "", ErrCredentialsNotFound
+	}
+	tokens, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	token, ok := tokens[context]
+	if !ok {
+		return "", ErrCredentialsNotFound
+	}
+	return token, nil
+}
+
+func (s *fileStore) Store(context, token string) error {
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+	tokens[context] = token
+	return s.save(tokens)
+}
+
+func (s *fileStore) Erase(context string) error {
+	if _, err := os.Stat(s.path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[context]; !ok {
+		return nil
+	}
+	delete(tokens, context)
+	return s.save(tokens)
+}
+
+func newGCM(pass string, salt []byte, iterations int) (cipher.AEAD, error) {
+	if iterations <= 0 {
+		return nil, errors.New("invalid credential file")
+	}
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(pass), salt, iterations, 32, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// helperStore runs an external program speaking the docker credential helper
+// protocol: "get", "store" or "erase" as argument, the server URL or a JSON
+// object on stdin and a JSON object on stdout. Contexts are keyed by their
+// server URL, utho://<context>.
+type helperStore struct {
+	program string
+}
+
+type helperCredentials struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+func helperServerURL(context string) string {
+	return "utho://" + context
+}
+
+// helperError is the failure of a credential helper, with what it printed
+// instead of the exit status of the program.
+type helperError struct {
+	msg string
+	err error
+}
+
+func (e *helperError) Error() string { return e.msg }
+
+func (e *helperError) Unwrap() error { return e.err }
+
+func (s *helperStore) run(action string, input []byte) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	c := exec.Command(s.program, action)
+	c.Stdin = bytes.NewReader(input)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		msg := strings.TrimSpace(stdout.String() + " " + stderr.String())
+		if strings.Contains(strings.ToLower(msg), "credentials not found") {
+			return nil, ErrCredentialsNotFound
+		}
+		if msg != "" {
+			return nil, &helperError{fmt.Sprintf("credential helper %s %s: %s", s.program, action, msg), err}
+		}
+		return nil, fmt.Errorf("credential helper %s %s: %w", s.program, action, err)
+	}
+	return stdout.Bytes(), nil
+}
+
+func (s *helperStore) Get(context string) (string, error) {
+	out, err := s.run("get", []byte(helperServerURL(context)))
+	if err != nil {
+		return "", err
+	}
+	var creds helperCredentials
+	if err := json.Unmarshal(out, &creds); err != nil {
+		return "", fmt.Errorf("credential helper %s get: invalid response: %w", s.program, err)
+	}
+	if creds.Secret == "" {
+		return "", ErrCredentialsNotFound
+	}
+	return creds.Secret, nil
+}
+
+func (s *helperStore) Store(context, token string) error {
+	input, err := json.Marshal(helperCredentials{
+		ServerURL: helperServerURL(context),
+		Username:  context,
+		Secret:    token,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.run("store", input)
+	return err
+}
+
+func (s *helperStore) Erase(context string) error {
+	_, err := s.run("erase", []byte(helperServerURL(context)))
+	if errors.Is(err, ErrCredentialsNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,303 @@
+package helper
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// withConfig points the configuration file into a temporary directory and
+// forgets the passphrase typed by earlier tests.
+func withConfig(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	viper.Set("config", filepath.Join(dir, "uthoctl.yaml"))
+	t.Cleanup(func() {
+		viper.Set("config", "")
+		passphrase = ""
+	})
+	passphrase = ""
+	return dir
+}
+
+func TestFileStore(t *testing.T) {
+	dir := withConfig(t)
+	t.Setenv("UTHO_PASSPHRASE", "correct horse")
+
+	cfg := &Config{Store: StoreFile}
+	store, err := cfg.CredentialStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("prod"); !errors.Is(err, ErrCredentialsNotFound) {
+		t.Fatalf("Get before Store: %v, want ErrCredentialsNotFound", err)
+	}
+	if err := store.Store("prod", "prod-token"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Store("staging", "staging-token"); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(dir, "uthoctl-credentials.enc")
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "prod-token") {
+		t.Errorf("token stored in clear:\n%s", data)
+	}
+	if info, _ := os.Stat(file); runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("credential file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// Read back with the passphrase typed again.
+	passphrase = ""
+	if got, err := store.Get("prod"); err != nil || got != "prod-token" {
+		t.Errorf("Get(prod) = %q, %v, want prod-token", got, err)
+	}
+	if err := store.Erase("prod"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("prod"); !errors.Is(err, ErrCredentialsNotFound) {
+		t.Errorf("Get after Erase: %v, want ErrCredentialsNotFound", err)
+	}
+	if got, err := store.Get("staging"); err != nil || got != "staging-token" {
+		t.Errorf("Get(staging) = %q, %v, want staging-token", got, err)
+	}
+}
+
+func TestFileStoreWrongPassphrase(t *testing.T) {
+	withConfig(t)
+	t.Setenv("UTHO_PASSPHRASE", "correct horse")
+	store, _ := (&Config{Store: StoreFile}).CredentialStore()
+	if err := store.Store("prod", "prod-token"); err != nil {
+		t.Fatal(err)
+	}
+
+	passphrase = ""
+	t.Setenv("UTHO_PASSPHRASE", "battery staple")
+	_, err := store.Get("prod")
+	if err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("Get with the wrong passphrase: %v", err)
+	}
+	if err := store.Store("prod", "other"); err == nil {
+		t.Error("Store with the wrong passphrase overwrote the file")
+	}
+}
+
+func TestFileStorePrompt(t *testing.T) {
+	withConfig(t)
+	t.Setenv("UTHO_PASSPHRASE", "")
+
+	var prompts strings.Builder
+	typed := []string{"one", "two"}
+	isTerminal, read := StdinIsTerminal, ReadPassword
+	StdinIsTerminal = func() bool { return true }
+	ReadPassword = func() ([]byte, error) {
+		answer := typed[0]
+		typed = typed[1:]
+		return []byte(answer), nil
+	}
+	Stderr = &prompts
+	defer func() {
+		StdinIsTerminal, ReadPassword = isTerminal, read
+		Stderr = os.Stderr
+	}()
+
+	store, _ := (&Config{Store: StoreFile}).CredentialStore()
+	if err := store.Store("prod", "prod-token"); err == nil || err.Error() != "passphrases do not match" {
+		t.Errorf("Store with mismatched passphrases: %v", err)
+	}
+	want := "Enter the credential file passphrase: \nConfirm the passphrase: \n"
+	if prompts.String() != want {
+		t.Errorf("prompts = %q, want %q", prompts.String(), want)
+	}
+
+	StdinIsTerminal = func() bool { return false }
+	if err := store.Store("prod", "prod-token"); err == nil || !strings.Contains(err.Error(), "UTHO_PASSPHRASE") {
+		t.Errorf("Store without a terminal: %v", err)
+	}
+}
+
+// fakeHelper writes a docker credential helper keeping credentials as files
+// of a directory, and logging the actions it runs.
+func fakeHelper(t *testing.T) (program, log string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake credential helper is a shell script")
+	}
+	dir := t.TempDir()
+	program = filepath.Join(dir, "docker-credential-fake")
+	log = filepath.Join(dir, "actions.log")
+	script := `#!/bin/sh
+dir=$(dirname "$0")/store
+mkdir -p "$dir"
+echo "$1" >> "$(dirname "$0")/actions.log"
+key() { printf '%s' "$1" | tr -c 'A-Za-z0-9' _; }
+case "$1" in
+get)
+	file="$dir/$(key "$(cat)")"
+	if [ ! -f "$file" ]; then echo "credentials not found in native keychain"; exit 1; fi
+	cat "$file";;
+store)
+	input=$(cat)
+	url=$(printf '%s' "$input" | sed 's/.*"ServerURL":"\([^"]*\)".*/\1/')
+	printf '%s' "$input" > "$dir/$(key "$url")";;
+erase)
+	file="$dir/$(key "$(cat)")"
+	if [ ! -f "$file" ]; then echo "credentials not found in native keychain"; exit 1; fi
+	rm "$file";;
+*)
+	echo "unknown action $1"; exit 1;;
+esac
+`
+	if err := os.WriteFile(program, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return program, log
+}
+
+func TestHelperStore(t *testing.T) {
+	program, log := fakeHelper(t)
+	store, err := (&Config{Store: StoreHelper, Helper: program}).CredentialStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Get("prod"); !errors.Is(err, ErrCredentialsNotFound) {
+		t.Fatalf("Get before Store: %v, want ErrCredentialsNotFound", err)
+	}
+	if err := store.Store("prod", "prod-token"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.Get("prod"); err != nil || got != "prod-token" {
+		t.Errorf("Get(prod) = %q, %v, want prod-token", got, err)
+	}
+	if err := store.Erase("prod"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Erase("prod"); err != nil {
+		t.Errorf("Erase of a missing token: %v", err)
+	}
+	if _, err := store.Get("prod"); !errors.Is(err, ErrCredentialsNotFound) {
+		t.Errorf("Get after Erase: %v, want ErrCredentialsNotFound", err)
+	}
+
+	actions, _ := os.ReadFile(log)
+	if want := "get\nstore\nget\nerase\nerase\nget\n"; string(actions) != want {
+		t.Errorf("helper ran %q, want %q", actions, want)
+	}
+
+	if _, err := (&Config{Store: StoreHelper}).CredentialStore(); err == nil {
+		t.Error("helper store without a program was accepted")
+	}
+	broken, _ := (&Config{Store: StoreHelper, Helper: program + "-missing"}).CredentialStore()
+	if _, err := broken.Get("prod"); err == nil || errors.Is(err, ErrCredentialsNotFound) {
+		t.Errorf("Get with a missing helper: %v", err)
+	}
+}
+
+func TestMigrateCredentials(t *testing.T) {
+	withConfig(t)
+	t.Setenv("UTHO_PASSPHRASE", "correct horse")
+	program, _ := fakeHelper(t)
+
+	cfg := &Config{}
+	cfg.SetContext("prod", &Context{Token: "prod-token"})
+	cfg.SetContext("staging", &Context{Token: "staging-token"})
+	cfg.SetContext("empty", &Context{})
+
+	tokens := func() map[string]string {
+		t.Helper()
+		store, err := cfg.CredentialStore()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := map[string]string{}
+		for _, name := range cfg.ContextNames() {
+			token, err := store.Get(name)
+			if errors.Is(err, ErrCredentialsNotFound) {
+				continue
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got[name] = token
+		}
+		return got
+	}
+	want := map[string]string{"prod": "prod-token", "staging": "staging-token"}
+
+	steps := []struct {
+		store, helper string
+	}{
+		{StoreFile, ""},
+		{StoreHelper, program},
+		{StorePlaintext, ""},
+	}
+	for _, step := range steps {
+		moved, err := cfg.MigrateCredentials(step.store, step.helper)
+		if err != nil || moved != 2 {
+			t.Fatalf("migrate to %s: moved %d, %v", step.store, moved, err)
+		}
+		if got := tokens(); len(got) != 2 || got["prod"] != want["prod"] || got["staging"] != want["staging"] {
+			t.Errorf("tokens in the %s store = %v, want %v", step.store, got, want)
+		}
+		if step.store != StorePlaintext && cfg.Contexts["prod"].Token != "" {
+			t.Errorf("token left in the config file after migrating to %s", step.store)
+		}
+	}
+
+	for _, step := range []struct{ from, store, helper string }{
+		{StorePlaintext, StorePlaintext, ""},
+		{StoreFile, StoreFile, ""},
+		{StoreHelper, StoreHelper, program},
+	} {
+		if step.from != StorePlaintext {
+			if _, err := cfg.MigrateCredentials(step.from, step.helper); err != nil {
+				t.Fatal(err)
+			}
+		}
+		_, err := cfg.MigrateCredentials(step.store, step.helper)
+		if ExitCode(err) != ExitUsage {
+			t.Errorf("migrate from %s to itself: %v, want a usage error", step.store, err)
+		}
+		if got := tokens(); len(got) != 2 {
+			t.Errorf("tokens in the %s store after migrating to itself = %v, want %v", step.store, got, want)
+		}
+	}
+}
+
+func TestMigrateCredentialsSaveFails(t *testing.T) {
+	dir := withConfig(t)
+	program, _ := fakeHelper(t)
+
+	cfg := &Config{}
+	cfg.SetContext("prod", &Context{Token: "prod-token"})
+	if _, err := cfg.MigrateCredentials(StoreHelper, program); err != nil {
+		t.Fatal(err)
+	}
+
+	// The config directory cannot be created under a file.
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	viper.Set("config", filepath.Join(blocker, "uthoctl.yaml"))
+	if _, err := cfg.MigrateCredentials(StorePlaintext, ""); err == nil {
+		t.Fatal("migrate with an unwritable config file succeeded")
+	}
+	if cfg.Store != StoreHelper || cfg.Helper != program {
+		t.Errorf("store after a failed save = %q %q, want the helper", cfg.Store, cfg.Helper)
+	}
+	store, _ := cfg.CredentialStore()
+	if got, err := store.Get("prod"); err != nil || got != "prod-token" {
+		t.Errorf("token in the helper after a failed save = %q, %v, want prod-token", got, err)
+	}
+}
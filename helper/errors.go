@@ -0,0 +1,145 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	"github.com/uthoplatforms/utho-go/utho"
+)
+
+// Exit codes returned by uthoctl, see ExitCode.
+const (
+	ExitOK       = 0
+	ExitError    = 1   // any error not listed below
+	ExitUsage    = 2   // invalid arguments, flags or request parameters
+	ExitAuth     = 3   // missing, invalid or unauthorized token
+	ExitNotFound = 4   // the resource does not exist
+	ExitAPI      = 5   // the API rejected the request or failed
+	ExitNetwork  = 6   // the API could not be reached
+	ExitAborted  = 7   // the user declined a confirmation
+	ExitChanges  = 8   // diff found changes between a manifest and the account
+	ExitTimeout  = 124 // --timeout expired, as with timeout(1)
+	ExitCanceled = 130 // interrupted by SIGINT or SIGTERM, as a shell reports ^C
+)
+
+var (
+	// ErrAborted is returned when the user declines a confirmation.
+	ErrAborted = errors.New("operation aborted")
+	// ErrNoToken is returned when no token is configured.
+	ErrNoToken = errors.New("no token found")
+	// ErrTimeout is the cause of the command context once --timeout expires.
+	ErrTimeout = errors.New("timed out")
+	// ErrChangesPending is returned by diff when applying the manifest
+	// would change the account, or the account differs from it. Only its
+	// exit code is reported.
+	ErrChangesPending = WithExitCode(errors.New("changes pending"), ExitChanges)
+	// ErrCanceled is the cause of the command context once SIGINT or SIGTERM
+	// is received.
+	ErrCanceled = errors.New("interrupted")
+)
+
+// codedError attaches an exit code to an error.
+type codedError struct {
+	Code int
+	Err  error
+}
+
+func (e *codedError) Error() string { return e.Err.Error() }
+
+func (e *codedError) Unwrap() error { return e.Err }
+
+// WithExitCode makes ExitCode return code for err.
+func WithExitCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{Code: code, Err: err}
+}
+
+// UsageError marks err as caused by invalid user input.
+func UsageError(err error) error {
+	return WithExitCode(err, ExitUsage)
+}
+
+// NotFoundError marks err as caused by a missing resource.
+func NotFoundError(err error) error {
+	return WithExitCode(err, ExitNotFound)
+}
+
+// ExitCode maps err to the exit code of the process. Errors carrying an
+// explicit code keep it, HTTP errors from utho-go are classified by status
+// code and the plain errors utho-go returns from the API message by their
+// text.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var exitErr *codedError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	if errors.Is(err, ErrAborted) {
+		return ExitAborted
+	}
+	if errors.Is(err, ErrNoToken) {
+		return ExitAuth
+	}
+	if errors.Is(err, ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		return ExitTimeout
+	}
+	if errors.Is(err, ErrCanceled) || errors.Is(err, context.Canceled) {
+		return ExitCanceled
+	}
+
+	var apiErr *utho.ErrorResponse
+	if errors.As(err, &apiErr) && apiErr.Response != nil {
+		switch code := apiErr.Response.StatusCode; {
+		case code == http.StatusUnauthorized || code == http.StatusForbidden:
+			return ExitAuth
+		case code == http.StatusNotFound:
+			return ExitNotFound
+		case code == http.StatusBadRequest || code == http.StatusUnprocessableEntity:
+			return ExitUsage
+		default:
+			return ExitAPI
+		}
+	}
+
+	var urlErr *url.Error
+	var netErr net.Error
+	if errors.As(err, &urlErr) || errors.As(err, &netErr) {
+		return ExitNetwork
+	}
+
+	// The API and utho-go report missing resources and refused tokens in
+	// the text of their errors alone, the programs uthoctl runs do not.
+	if !fromAPI(err) {
+		return ExitError
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "notfound") || strings.Contains(msg, "not found") || strings.Contains(msg, "does not exist"):
+		return ExitNotFound
+	case strings.Contains(msg, "unauthorized") || strings.Contains(msg, "unauthenticated") ||
+		strings.Contains(msg, "invalid token") || strings.Contains(msg, "authorization"):
+		return ExitAuth
+	}
+	return ExitError
+}
+
+// fromAPI reports whether err may come from the API or utho-go, rather
+// than from a program or file uthoctl uses, such as a credential helper
+// missing from the PATH.
+func fromAPI(err error) bool {
+	var execErr *exec.Error
+	var exitErr *exec.ExitError
+	var pathErr *fs.PathError
+	return !errors.As(err, &execErr) && !errors.As(err, &exitErr) && !errors.As(err, &pathErr)
+}
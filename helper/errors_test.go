@@ -0,0 +1,58 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"testing"
+
+	"github.com/uthoplatforms/utho-go/utho"
+)
+
+func apiError(status int) error {
+	req, _ := http.NewRequest(http.MethodGet, "https://api.utho.com/v2/cloud", nil)
+	return &utho.ErrorResponse{Response: &http.Response{StatusCode: status, Request: req}}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOK},
+		{"plain error", errors.New("boom"), ExitError},
+		{"aborted", ErrAborted, ExitAborted},
+		{"wrapped aborted", fmt.Errorf("delete: %w", ErrAborted), ExitAborted},
+		{"no token", fmt.Errorf("%w for context %q", ErrNoToken, "default"), ExitAuth},
+		{"usage", UsageError(errors.New("accepts 1 arg(s), received 0")), ExitUsage},
+		{"not found", NotFoundError(errors.New("context \"prod\" not found")), ExitNotFound},
+		{"explicit code wins", WithExitCode(apiError(http.StatusNotFound), ExitError), ExitError},
+		{"http 401", apiError(http.StatusUnauthorized), ExitAuth},
+		{"http 403", apiError(http.StatusForbidden), ExitAuth},
+		{"http 404", apiError(http.StatusNotFound), ExitNotFound},
+		{"http 400", apiError(http.StatusBadRequest), ExitUsage},
+		{"http 422", apiError(http.StatusUnprocessableEntity), ExitUsage},
+		{"http 429", apiError(http.StatusTooManyRequests), ExitAPI},
+		{"http 500", apiError(http.StatusInternalServerError), ExitAPI},
+		{"http 503", apiError(http.StatusServiceUnavailable), ExitAPI},
+		{"network", &url.Error{Op: "Get", URL: "https://api.utho.com/v2/cloud", Err: errors.New("connection refused")}, ExitNetwork},
+		{"sdk NotFound", errors.New("NotFound"), ExitNotFound},
+		{"sdk resource not found", errors.New("auto scaling policy not found"), ExitNotFound},
+		{"api unauthorized message", errors.New("Unauthorized"), ExitAuth},
+		{"missing program", fmt.Errorf("credential helper docker-credential-pass get: %w", &exec.Error{Name: "docker-credential-pass", Err: exec.ErrNotFound}), ExitError},
+		{"failed program", &helperError{"credential helper pass get: key not found", &exec.ExitError{}}, ExitError},
+		{"missing file", &fs.PathError{Op: "open", Path: "env.yaml", Err: fs.ErrNotExist}, ExitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,71 +1,171 @@
 package helper
 
 import (
-	"bufio"
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/spf13/viper"
 	"github.com/uthoplatforms/utho-go/utho"
 )
 
-func SaveToken(token string) {
-	home, err := os.UserHomeDir()
+// Stdin, Stdout and Stderr are used for prompts and messages outside of the
+// command output. Tests replace them.
+var (
+	Stdin  io.Reader = os.Stdin
+	Stdout io.Writer = os.Stdout
+	Stderr io.Writer = os.Stderr
+)
+
+// SaveToken stores token in the active context, creating it if needed. The
+// first context saved becomes the current one.
+func SaveToken(token string) error {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	name := cfg.ContextName()
+	if cfg.Context() == nil {
+		cfg.SetContext(name, &Context{})
+	}
+	store, err := cfg.CredentialStore()
 	if err != nil {
-		fmt.Println("Error finding home directory:", err)
-		os.Exit(1)
+		return err
+	}
+	if err := store.Store(name, token); err != nil {
+		return fmt.Errorf("error saving token: %w", err)
+	}
+	if cfg.CurrentContext == "" {
+		cfg.CurrentContext = name
 	}
 
-	configDir := filepath.Join(home, ".config")
-	if err := os.MkdirAll(configDir, os.ModePerm); err != nil {
-		fmt.Println("Error creating config directory:", err)
-		os.Exit(1)
+	if err := cfg.Save(); err != nil {
+		return err
 	}
 
-	viper.SetConfigType("yaml")
-	viper.Set("token", token)
+	configFile, _ := ConfigFile()
+	fmt.Fprintf(Stdout, "Token saved successfully at %s (context: %s)\n", configFile, name)
+	return nil
+}
 
-	configFile := filepath.Join(configDir, "uthoctl.yaml")
-	if err := viper.WriteConfigAs(configFile); err != nil {
-		fmt.Println("Error writing config file:", err)
-		os.Exit(1)
+// RemoveToken deletes the token of the active context, keeping its other
+// settings.
+func RemoveToken() error {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return err
 	}
 
-	fmt.Println("Token saved successfully at", configFile)
+	name := cfg.ContextName()
+	store, err := cfg.CredentialStore()
+	if err != nil {
+		return err
+	}
+	if _, err := store.Get(name); errors.Is(err, ErrCredentialsNotFound) {
+		fmt.Fprintf(Stdout, "No token saved in context %s\n", name)
+		return nil
+	}
+	if err := store.Erase(name); err != nil {
+		return fmt.Errorf("error removing token: %w", err)
+	}
+
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(Stdout, "Token removed from context %s\n", name)
+	return nil
 }
 
-func NewUthoClient() (utho.Client, error) {
-	token := viper.GetString("token")
-	if token == "" {
-		return nil, errors.New("no token found. please login first")
+// MaskToken hides all but the first and last four characters of token.
+func MaskToken(token string) string {
+	if len(token) <= 8 {
+		return strings.Repeat("*", len(token))
 	}
+	return token[:4] + strings.Repeat("*", len(token)-8) + token[len(token)-4:]
+}
 
-	clinet, err := utho.NewClient(token)
+// NewUthoClient returns a client for the resolved token. Its requests are
+// cancelled when ctx is done.
+func NewUthoClient(ctx context.Context) (utho.Client, error) {
+	token, _, err := ResolveToken()
+	if errors.Is(err, ErrNoToken) && Replaying() {
+		// A cassette is replayed with its token scrubbed, none is needed.
+		token, err = redacted, nil
+	}
 	if err != nil {
 		return nil, err
 	}
-	return clinet, err
+	return NewUthoClientWithToken(ctx, token)
 }
 
-func Ask() bool {
-	reader := bufio.NewReader(os.Stdin)
+// NewUthoClientWithToken returns a client for token instead of the resolved
+// one, eg: to check a token before saving it.
+func NewUthoClientWithToken(ctx context.Context, token string) (utho.Client, error) {
+	var options []utho.UthoOption
+	apiURL, err := APIURL()
+	if err != nil {
+		return nil, err
+	}
+	if apiURL != "" {
+		options = append(options, utho.WithBaseURL(apiURL))
+	}
+	httpClient, err := newHTTPClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, utho.WithHTTPClient(httpClient))
 
-	fmt.Print("Are you sure you want to proceed? (y/n): ")
-	input, err := reader.ReadString('\n')
+	clinet, err := utho.NewClient(token, options...)
 	if err != nil {
-		fmt.Println("Error reading input:", err)
-		os.Exit(1)
+		return nil, err
+	}
+	return clinet, err
+}
+
+// newHTTPClient returns the HTTP client used by utho-go. Requests are
+// retried as set by RetryPolicy, each attempt being traced when --verbose or
+// --debug is set, and recorded to or replayed from the cassette of --record
+// or --replay. The action IDs found in responses are kept for
+// TriggeredActions. utho-go does not take a context, so ctx is set on every
+// request by the transport.
+func newHTTPClient(ctx context.Context) (*http.Client, error) {
+	record, replay := CassetteDirs()
+	transport := http.DefaultTransport
+	switch {
+	case record != "":
+		transport = &recordingTransport{next: transport, dir: record}
+	case replay != "":
+		replayer, err := newReplayTransport(replay)
+		if err != nil {
+			return nil, err
+		}
+		transport = replayer
+	}
+	if level := LogLevel(); level > LogOff {
+		transport = &loggingTransport{next: transport, level: level}
 	}
 
-	input = strings.TrimSpace(input)
-	if strings.ToLower(input) == "y" {
-		return true
-	} else {
-		return false
+	retries, mutating, err := RetryPolicy()
+	if err != nil {
+		return nil, err
+	}
+	retry := newRetryTransport(transport, retries, mutating)
+	if replay != "" {
+		// Replayed failures are retried without waiting.
+		retry.maxDelay = 0
 	}
+	transport = retry
+	transport = &actionTransport{next: transport}
+	if ctx != nil {
+		transport = &contextTransport{next: transport, ctx: ctx}
+	}
+
+	return &http.Client{Transport: transport, Timeout: 300 * time.Second}, nil
 }
 
 func StringToBool(str string) (bool, error) {
@@ -76,6 +176,6 @@ func StringToBool(str string) (bool, error) {
 	case "false", "0", "no", "n":
 		return false, nil
 	default:
-		return false, fmt.Errorf("cannot convert %s to bool", str)
+		return false, fmt.Errorf("cannot convert %q to bool", str)
 	}
 }
@@ -0,0 +1,122 @@
+package helper
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/uthoplatforms/utho-go/utho"
+)
+
+// Kinds of resources whose names are accepted in place of their IDs.
+const (
+	KindInstance     = "instance"
+	KindFirewall     = "firewall"
+	KindVPC          = "VPC"
+	KindLoadbalancer = "load balancer"
+	KindTargetGroup  = "target group"
+	KindCluster      = "cluster"
+	KindAutoscaling  = "auto scaling group"
+)
+
+// namedResource is the ID and name of a resource, as matched by Resolve.
+type namedResource struct {
+	ID   string
+	Name string
+}
+
+// Resolve returns the ID of the resource of kind that ref designates: ref
+// itself when it is numeric, else the resource with that ID, or the only one
+// with that name (the hostname of instances and clusters). A name shared by
+// several resources is a usage error listing them.
+func Resolve(client utho.Client, kind, ref string) (string, error) {
+	if IsID(ref) {
+		return ref, nil
+	}
+	list, ok := resourceLists[kind]
+	if !ok {
+		return "", fmt.Errorf("cannot resolve names of %s", kind)
+	}
+	resources, err := list(client)
+	if err != nil {
+		return "", err
+	}
+	return matchName(kind, ref, resources)
+}
+
+func matchName(kind, ref string, resources []namedResource) (string, error) {
+	var matches []namedResource
+	for _, r := range resources {
+		if r.ID == ref {
+			return r.ID, nil
+		}
+		if r.Name == ref {
+			matches = append(matches, r)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", NotFoundError(fmt.Errorf("no %s with ID or name %q", kind, ref))
+	case 1:
+		return matches[0].ID, nil
+	}
+	candidates := make([]string, len(matches))
+	for i, m := range matches {
+		candidates[i] = m.ID
+	}
+	return "", UsageError(fmt.Errorf("%q matches %d %ss, use one of their IDs: %s", ref, len(matches), kind, strings.Join(candidates, ", ")))
+}
+
+// IsID reports whether ref is taken as an ID without looking it up: it is
+// numeric, or empty.
+func IsID(ref string) bool {
+	for _, r := range ref {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// resourceLists list the resources of each kind.
+var resourceLists = map[string]func(client utho.Client) ([]namedResource, error){
+	KindInstance: func(client utho.Client) ([]namedResource, error) {
+		instances, err := client.CloudInstances().List()
+		return named(instances, err, func(i utho.CloudInstance) namedResource { return namedResource{i.ID, i.Hostname} })
+	},
+	KindFirewall: func(client utho.Client) ([]namedResource, error) {
+		firewalls, err := client.Firewall().List()
+		return named(firewalls, err, func(f utho.Firewall) namedResource { return namedResource{f.ID, f.Name} })
+	},
+	KindVPC: func(client utho.Client) ([]namedResource, error) {
+		vpcs, err := client.Vpc().List()
+		return named(vpcs, err, func(v utho.Vpc) namedResource { return namedResource{v.ID, v.Name} })
+	},
+	KindLoadbalancer: func(client utho.Client) ([]namedResource, error) {
+		loadbalancers, err := client.Loadbalancers().List()
+		return named(loadbalancers, err, func(l utho.Loadbalancer) namedResource { return namedResource{l.ID, l.Name} })
+	},
+	KindTargetGroup: func(client utho.Client) ([]namedResource, error) {
+		targetgroups, err := client.TargetGroup().List()
+		return named(targetgroups, err, func(t utho.TargetGroup) namedResource { return namedResource{t.ID, t.Name} })
+	},
+	KindCluster: func(client utho.Client) ([]namedResource, error) {
+		clusters, err := client.Kubernetes().List()
+		return named(clusters, err, func(k utho.K8s) namedResource { return namedResource{k.ID, k.Hostname} })
+	},
+	KindAutoscaling: func(client utho.Client) ([]namedResource, error) {
+		groups, err := client.AutoScaling().List()
+		return named(groups, err, func(g utho.Groups) namedResource { return namedResource{g.ID, g.Name} })
+	},
+}
+
+func named[T any](items []T, err error, f func(T) namedResource) ([]namedResource, error) {
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]namedResource, len(items))
+	for i, item := range items {
+		resources[i] = f(item)
+	}
+	return resources, nil
+}
@@ -0,0 +1,170 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultRetries is the number of retries of a failed request when neither
+// --retries nor the context sets it.
+const DefaultRetries = 3
+
+// RetryPolicy returns the number of retries and whether mutating requests
+// (POST, PUT, PATCH, DELETE) are retried too, from --retries and
+// --retry-mutating (or UTHO_RETRIES and UTHO_RETRY_MUTATING), else from the
+// active context.
+func RetryPolicy() (retries int, mutating bool, err error) {
+	retries, mutating = DefaultRetries, false
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return 0, false, err
+	}
+	if ctx := cfg.Context(); ctx != nil {
+		if ctx.Retries != nil {
+			retries = *ctx.Retries
+		}
+		mutating = ctx.RetryMutating
+	}
+
+	if viper.IsSet("retries") {
+		retries = viper.GetInt("retries")
+	}
+	if viper.IsSet("retry_mutating") {
+		mutating = viper.GetBool("retry_mutating")
+	}
+	if retries < 0 {
+		return 0, false, UsageError(fmt.Errorf("invalid number of retries %d", retries))
+	}
+	return retries, mutating, nil
+}
+
+// retryTransport retries requests failing with a connection error, a 429 or
+// a 5xx response, waiting with an exponential backoff and jitter or as long
+// as the Retry-After header asks. Only idempotent requests are retried unless
+// mutating is set, as a create retried after a lost response may run twice.
+type retryTransport struct {
+	next     http.RoundTripper
+	retries  int
+	mutating bool
+
+	baseDelay time.Duration
+	maxDelay  time.Duration
+}
+
+func newRetryTransport(next http.RoundTripper, retries int, mutating bool) *retryTransport {
+	return &retryTransport{
+		next:      next,
+		retries:   retries,
+		mutating:  mutating,
+		baseDelay: 500 * time.Millisecond,
+		maxDelay:  30 * time.Second,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.retryable(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(req)
+		if attempt >= t.retries || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		reason := ""
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = resp.Status
+			if after, ok := retryAfter(resp); ok {
+				delay = min(after, t.maxDelay)
+			}
+			resp.Body.Close()
+		}
+
+		if LogLevel() >= LogTimings {
+			fmt.Fprintf(LogOutput, "* %s %s: %s, retrying in %s (retry %d/%d)\n",
+				req.Method, req.URL, reason, delay.Round(time.Millisecond), attempt+1, t.retries)
+		}
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *retryTransport) retryable(req *http.Request) bool {
+	if t.retries == 0 {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return t.mutating
+	}
+}
+
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return req.Context().Err() == nil && !errors.Is(err, ErrNotRecorded) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// backoff returns a random delay between half and all of baseDelay*2^attempt,
+// capped to maxDelay.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	delay := t.baseDelay << attempt
+	if delay <= 0 || delay > t.maxDelay {
+		delay = t.maxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// retryAfter parses the Retry-After header, either a number of seconds or an
+// HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,106 @@
+package helper
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first failures requests with status.
+func flakyServer(t *testing.T, failures int32, status int, header http.Header) (*httptest.Server, *int32) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method == http.MethodPost && string(body) != `{"name":"web"}` {
+			t.Errorf("request body = %q on attempt %d", body, atomic.LoadInt32(&calls)+1)
+		}
+		if atomic.AddInt32(&calls, 1) <= failures {
+			for name, values := range header {
+				w.Header()[name] = values
+			}
+			w.WriteHeader(status)
+			return
+		}
+		io.WriteString(w, `{"status":"success"}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func testRetryClient(retries int, mutating bool) *http.Client {
+	transport := newRetryTransport(http.DefaultTransport, retries, mutating)
+	transport.baseDelay = time.Millisecond
+	transport.maxDelay = 10 * time.Millisecond
+	return &http.Client{Transport: transport}
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		status    int
+		failures  int32
+		retries   int
+		mutating  bool
+		wantCalls int32
+		wantCode  int
+	}{
+		{"get recovers from 502", http.MethodGet, http.StatusBadGateway, 2, 3, false, 3, http.StatusOK},
+		{"get recovers from 429", http.MethodGet, http.StatusTooManyRequests, 1, 3, false, 2, http.StatusOK},
+		{"get gives up", http.MethodGet, http.StatusServiceUnavailable, 5, 2, false, 3, http.StatusServiceUnavailable},
+		{"client errors are not retried", http.MethodGet, http.StatusNotFound, 1, 3, false, 1, http.StatusNotFound},
+		{"retries disabled", http.MethodGet, http.StatusBadGateway, 1, 0, false, 1, http.StatusBadGateway},
+		{"post not retried by default", http.MethodPost, http.StatusBadGateway, 1, 3, false, 1, http.StatusBadGateway},
+		{"post retried when mutating", http.MethodPost, http.StatusBadGateway, 1, 3, true, 2, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := flakyServer(t, tt.failures, tt.status, nil)
+
+			var body io.Reader
+			if tt.method == http.MethodPost {
+				body = strings.NewReader(`{"name":"web"}`)
+			}
+			req, _ := http.NewRequest(tt.method, srv.URL, body)
+			resp, err := testRetryClient(tt.retries, tt.mutating).Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantCode {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantCode)
+			}
+			if got := atomic.LoadInt32(calls); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryTransportHonorsRetryAfter(t *testing.T) {
+	srv, calls := flakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}})
+
+	transport := newRetryTransport(http.DefaultTransport, 1, false)
+	transport.baseDelay = time.Millisecond
+	client := &http.Client{Transport: transport}
+
+	start := time.Now()
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want at least the 1s of Retry-After", elapsed)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
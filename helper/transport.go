@@ -0,0 +1,203 @@
+package helper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Log levels of the HTTP trace written by the client.
+const (
+	LogOff     = iota
+	LogTimings // one line per request with its status and duration (--verbose)
+	LogBodies  // headers and bodies of every request and response (--debug)
+)
+
+// LogOutput receives the HTTP trace.
+var LogOutput io.Writer = os.Stderr
+
+// LogLevel returns the trace level selected with --verbose or --debug.
+func LogLevel() int {
+	switch {
+	case viper.GetBool("debug"):
+		return LogBodies
+	case viper.GetBool("verbose"):
+		return LogTimings
+	default:
+		return LogOff
+	}
+}
+
+// loggingTransport traces the requests sent through next. The Authorization
+// header and the secret fields of the bodies are always redacted.
+type loggingTransport struct {
+	next  http.RoundTripper
+	level int
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.level >= LogBodies {
+		body, err := peekBody(&req.Body)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(LogOutput, "> %s %s\n", req.Method, req.URL)
+		logHeaders(">", req.Header)
+		logBody(">", body)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(LogOutput, "* %s %s failed after %s: %v\n", req.Method, req.URL, elapsed, err)
+		return nil, err
+	}
+
+	if t.level >= LogBodies {
+		body, err := peekBody(&resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(LogOutput, "< %s (%s)\n", resp.Status, elapsed)
+		logHeaders("<", resp.Header)
+		logBody("<", body)
+	} else {
+		fmt.Fprintf(LogOutput, "* %s %s %s (%s)\n", req.Method, req.URL, resp.Status, elapsed)
+	}
+	return resp, nil
+}
+
+// peekBody reads a request or response body and puts back a copy so it can
+// still be sent or decoded.
+func peekBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+func logHeaders(prefix string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := strings.Join(header[name], ", ")
+		if strings.EqualFold(name, "Authorization") {
+			value = "REDACTED"
+		}
+		fmt.Fprintf(LogOutput, "%s %s: %s\n", prefix, name, value)
+	}
+}
+
+// secretFields matches the names of the JSON fields whose values are not
+// traced, such as root_password or the secret_key of object storage, but
+// not lists of IDs such as sshkeys.
+var secretFields = regexp.MustCompile(`(?i)password|secret|token|key$`)
+
+// jsonField matches a JSON field with a string, number or boolean value.
+var jsonField = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"(\s*:\s*)("(?:[^"\\]|\\.)*"|[-+.0-9eE]+|true|false)`)
+
+// redactBody replaces the values of the secret fields of a JSON body,
+// leaving the rest of it as sent.
+func redactBody(body []byte) []byte {
+	return jsonField.ReplaceAllFunc(body, func(field []byte) []byte {
+		m := jsonField.FindSubmatch(field)
+		if !secretFields.Match(m[1]) {
+			return field
+		}
+		return []byte(fmt.Sprintf("\"%s\"%s%q", m[1], m[2], redacted))
+	})
+}
+
+// RedactFields returns a copy of v, a struct, a pointer or a slice of them,
+// with the values of its secret string fields redacted as in the trace.
+// Fields are named after their json tag.
+func RedactFields(v any) any {
+	if v == nil {
+		return nil
+	}
+	return redactValue(reflect.ValueOf(v)).Interface()
+}
+
+func redactValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		p := reflect.New(v.Elem().Type())
+		p.Elem().Set(redactValue(v.Elem()))
+		return p
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(redactValue(v.Index(i)))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "" {
+				name = f.Name
+			}
+			if f.Type.Kind() == reflect.String && c.Field(i).String() != "" && secretFields.MatchString(name) {
+				c.Field(i).SetString(redacted)
+				continue
+			}
+			c.Field(i).Set(redactValue(c.Field(i)))
+		}
+		return c
+	}
+	return v
+}
+
+func logBody(prefix string, body []byte) {
+	fmt.Fprintln(LogOutput, prefix)
+	if len(body) == 0 {
+		return
+	}
+	body = redactBody(body)
+	for _, line := range strings.Split(strings.TrimRight(string(body), "\n"), "\n") {
+		fmt.Fprintf(LogOutput, "%s %s\n", prefix, line)
+	}
+}
+
+// contextTransport sends the requests of next with ctx, so they are
+// cancelled on timeout or interrupt.
+type contextTransport struct {
+	next http.RoundTripper
+	ctx  context.Context
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.next.RoundTrip(req.WithContext(t.ctx))
+}
@@ -0,0 +1,108 @@
+package helper
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoggingTransportRedactsSecrets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"status":"success","password":"s3cret-pass"}`)
+	}))
+	defer srv.Close()
+
+	var log bytes.Buffer
+	LogOutput = &log
+	defer func() { LogOutput = os.Stderr }()
+
+	for _, level := range []int{LogTimings, LogBodies} {
+		log.Reset()
+		client := &http.Client{Transport: &loggingTransport{next: http.DefaultTransport, level: level}}
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/v2/cloud/deploy", strings.NewReader(`{"hostname":"web","root_password":"hunter2"}`))
+		req.Header.Set("Authorization", "Bearer secret-token")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if string(body) != `{"status":"success","password":"s3cret-pass"}` {
+			t.Errorf("level %d: response body = %q, want it untouched", level, body)
+		}
+		for _, secret := range []string{"secret-token", "hunter2", "s3cret-pass"} {
+			if strings.Contains(log.String(), secret) {
+				t.Errorf("level %d: %s leaked in trace:\n%s", level, secret, log.String())
+			}
+		}
+		if !strings.Contains(log.String(), "200 OK") {
+			t.Errorf("level %d: status missing from trace:\n%s", level, log.String())
+		}
+	}
+
+	for _, want := range []string{
+		"> Authorization: REDACTED",
+		`> {"hostname":"web","root_password":"REDACTED"}`,
+		`< {"status":"success","password":"REDACTED"}`,
+	} {
+		if !strings.Contains(log.String(), want) {
+			t.Errorf("debug trace has no %s:\n%s", want, log.String())
+		}
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name, body, want string
+	}{
+		{"no secret", `{"hostname":"web","planid":"10045","sshkeys":"432,331"}`, `{"hostname":"web","planid":"10045","sshkeys":"432,331"}`},
+		{"root password", `{"hostname":"web","root_password":"hunter2"}`, `{"hostname":"web","root_password":"REDACTED"}`},
+		{"instance password", `{"status":"success","cloudid":"1013","password":"p4ss"}`, `{"status":"success","cloudid":"1013","password":"REDACTED"}`},
+		{"object storage keys", `{"access_key":"AKIA","secret_key":"abc\"def"}`, `{"access_key":"REDACTED","secret_key":"REDACTED"}`},
+		{"nested token", `{"user":{"name":"ops","Token":"t0k"}}`, `{"user":{"name":"ops","Token":"REDACTED"}}`},
+		{"number", `{"pin_secret": 1234, "size": 24}`, `{"pin_secret": "REDACTED", "size": 24}`},
+		{"indented", "{\n  \"password\": \"p4ss\"\n}", "{\n  \"password\": \"REDACTED\"\n}"},
+		{"secret as a value", `{"name":"password","note":"secret"}`, `{"name":"password","note":"secret"}`},
+		{"not json", "password=p4ss", "password=p4ss"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(redactBody([]byte(tt.body))); got != tt.want {
+				t.Errorf("redactBody(%s) = %s, want %s", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactFields(t *testing.T) {
+	type node struct {
+		Name  string `json:"name"`
+		Token string `json:"token,omitempty"`
+	}
+	type params struct {
+		Hostname     string `json:"hostname"`
+		RootPassword string `json:"root_password"`
+		SecretKey    string
+		Sshkeys      string `json:"sshkeys"`
+		Nodes        []node `json:"nodes"`
+		Owner        *node  `json:"owner"`
+	}
+	in := params{Hostname: "web", RootPassword: "hunter2", SecretKey: "abc", Sshkeys: "432", Nodes: []node{{"a", "t0k"}, {"b", ""}}, Owner: &node{"ops", "t1k"}}
+
+	got := RedactFields(in).(params)
+	want := params{Hostname: "web", RootPassword: "REDACTED", SecretKey: "REDACTED", Sshkeys: "432", Nodes: []node{{"a", "REDACTED"}, {"b", ""}}, Owner: &node{"ops", "REDACTED"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RedactFields() = %+v, want %+v", got, want)
+	}
+	if in.RootPassword != "hunter2" || in.Nodes[0].Token != "t0k" || in.Owner.Token != "t1k" {
+		t.Errorf("RedactFields() changed its argument: %+v", in)
+	}
+}
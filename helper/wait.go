@@ -0,0 +1,150 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Defaults of --wait-timeout and --poll-interval.
+const (
+	DefaultWaitTimeout  = 10 * time.Minute
+	DefaultPollInterval = 5 * time.Second
+)
+
+// ErrFailed is returned when a waited for resource lands in an error state.
+var ErrFailed = errors.New("failed")
+
+// WaitState is what a poll tells about the waited for resource.
+type WaitState int
+
+const (
+	WaitPending WaitState = iota // not there yet, poll again
+	WaitDone                     // ready, or gone when waiting for a delete
+	WaitFailed                   // in an error state, waiting is pointless
+)
+
+// StatusState classifies the status reported by the API for a resource
+// being created. A resource without a status, such as some VPCs, is ready as
+// soon as it can be read.
+func StatusState(status string) WaitState {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "", "active", "running", "ready", "success", "completed", "online":
+		return WaitDone
+	case "error", "failed", "failure", "suspended", "terminated", "deleted":
+		return WaitFailed
+	default:
+		return WaitPending
+	}
+}
+
+// WaitOptions bound the polling of Wait.
+type WaitOptions struct {
+	Timeout  time.Duration
+	Interval time.Duration
+}
+
+// Wait calls poll every opts.Interval until it reports the resource done or
+// failed, or opts.Timeout expires. what names the resource in messages, eg:
+// "instance 1234". poll sends its requests with the ctx it is given, which is
+// cancelled on timeout, and returns the status to show while waiting.
+//
+// On a terminal stderr a spinner shows the status and the elapsed time,
+// otherwise each status change is printed on its own line.
+func Wait(ctx context.Context, opts WaitOptions, what string, poll func(ctx context.Context) (string, WaitState, error)) error {
+	if opts.Interval <= 0 {
+		return UsageError(fmt.Errorf("invalid poll interval %s", opts.Interval))
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, opts.Timeout,
+			fmt.Errorf("%w waiting for %s after %s", ErrTimeout, what, opts.Timeout))
+		defer cancel()
+	}
+
+	progress := newProgress(what)
+	defer progress.done()
+
+	pollTimer := time.NewTimer(0)
+	defer pollTimer.Stop()
+	var spin <-chan time.Time
+	if progress.tty {
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		spin = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-spin:
+			progress.draw()
+		case <-pollTimer.C:
+			status, state, err := poll(ctx)
+			if err != nil {
+				if cause := context.Cause(ctx); cause != nil {
+					return cause
+				}
+				return err
+			}
+			progress.update(status)
+			switch state {
+			case WaitDone:
+				return nil
+			case WaitFailed:
+				return WithExitCode(fmt.Errorf("%s %w: status is %s", what, ErrFailed, status), ExitAPI)
+			}
+			pollTimer.Reset(opts.Interval)
+		}
+	}
+}
+
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// progress reports a wait on stderr.
+type progress struct {
+	what   string
+	status string
+	start  time.Time
+	frame  int
+	tty    bool
+}
+
+func newProgress(what string) *progress {
+	f, ok := Stderr.(*os.File)
+	return &progress{what: what, start: time.Now(), tty: ok && term.IsTerminal(int(f.Fd()))}
+}
+
+func (p *progress) update(status string) {
+	if status == p.status {
+		return
+	}
+	p.status = status
+	if p.tty {
+		p.draw()
+	} else if status != "" {
+		fmt.Fprintf(Stderr, "Waiting for %s: %s\n", p.what, status)
+	}
+}
+
+func (p *progress) draw() {
+	p.frame = (p.frame + 1) % len(spinnerFrames)
+	line := fmt.Sprintf("%s Waiting for %s", spinnerFrames[p.frame], p.what)
+	if p.status != "" {
+		line += ": " + p.status
+	}
+	fmt.Fprintf(Stderr, "\r\033[K%s (%s)", line, time.Since(p.start).Round(time.Second))
+}
+
+// done clears the spinner line.
+func (p *progress) done() {
+	if p.tty {
+		fmt.Fprint(Stderr, "\r\033[K")
+	}
+}
@@ -0,0 +1,61 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWait(t *testing.T) {
+	Stderr = io.Discard
+	defer func() { Stderr = os.Stderr }()
+	opts := WaitOptions{Timeout: time.Second, Interval: time.Millisecond}
+
+	polls := 0
+	err := Wait(context.Background(), opts, "instance 1", func(ctx context.Context) (string, WaitState, error) {
+		polls++
+		if polls < 3 {
+			return "Installing", WaitPending, nil
+		}
+		return "Active", WaitDone, nil
+	})
+	if err != nil || polls != 3 {
+		t.Errorf("Wait() = %v after %d polls, want nil after 3", err, polls)
+	}
+
+	boom := errors.New("boom")
+	err = Wait(context.Background(), opts, "instance 1", func(ctx context.Context) (string, WaitState, error) {
+		return "", WaitPending, boom
+	})
+	if !errors.Is(err, boom) {
+		t.Errorf("Wait() = %v, want the poll error", err)
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	err = Wait(ctx, opts, "instance 1", func(ctx context.Context) (string, WaitState, error) {
+		cancel(ErrCanceled)
+		return "Installing", WaitPending, nil
+	})
+	if ExitCode(err) != ExitCanceled {
+		t.Errorf("Wait() = %v once interrupted, want exit code %d", err, ExitCanceled)
+	}
+}
+
+func TestStatusState(t *testing.T) {
+	for status, want := range map[string]WaitState{
+		"Active":     WaitDone,
+		"running":    WaitDone,
+		"":           WaitDone,
+		"Installing": WaitPending,
+		"Pending":    WaitPending,
+		"Error":      WaitFailed,
+		"failed":     WaitFailed,
+	} {
+		if got := StatusState(status); got != want {
+			t.Errorf("StatusState(%q) = %d, want %d", status, got, want)
+		}
+	}
+}
@@ -0,0 +1,498 @@
+package fakeapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/uthoplatforms/utho-go/utho"
+)
+
+const deleteServerConfirm = "I am aware this action will delete data and server permanently"
+
+func instanceID(i *utho.CloudInstance) string { return i.ID }
+func clusterID(k *utho.K8s) string            { return k.ID }
+func groupID(g *utho.Groups) string           { return g.ID }
+
+func (s *Server) registerCompute() {
+	// Cloud instances
+	s.handle("POST", "cloud/deploy", func(r *request) (int, any) {
+		var p utho.CreateCloudInstanceParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		if err := required("dcslug", p.Dcslug, "image", p.Image, "planid", p.Planid); err != nil {
+			return badRequest(err)
+		}
+		if len(p.Cloud) == 0 || p.Cloud[0].Hostname == "" {
+			return badRequest(errors.New("hostname is required"))
+		}
+
+		billing := p.Billingcycle
+		if billing == "" {
+			billing = "hourly"
+		}
+		backups := "0"
+		if p.Enablebackup == "on" {
+			backups = "1"
+		}
+		id := s.id()
+		ip := fmt.Sprintf("203.0.113.%d", len(s.Instances)+10)
+		s.Instances = append(s.Instances, utho.CloudInstance{
+			ID:           id,
+			Hostname:     p.Cloud[0].Hostname,
+			CPU:          "1",
+			RAM:          "1024",
+			PlanDisksize: 25,
+			Disksize:     25,
+			Status:       s.provision(id),
+			IP:           ip,
+			Billingcycle: billing,
+			Powerstatus:  "Running",
+			CreatedAt:    s.now(),
+			Features:     utho.Features{Backups: backups},
+			Image:        utho.Image{Name: p.Image, Image: p.Image},
+			Dclocation:   utho.Dclocation{Location: p.Dcslug, Dc: p.Dcslug},
+			Snapshots:    []utho.Snapshots{},
+		})
+		s.action("deploy", "Cloud", id)
+		return ok(utho.CreateCloudInstanceResponse{
+			ID:       id,
+			Password: "fake-password",
+			Ipv4:     ip,
+			Status:   "success",
+			Message:  "Cloud server deploy in process",
+		})
+	})
+	s.handle("GET", "cloud", func(r *request) (int, any) {
+		for i := range s.Instances {
+			s.settle(s.Instances[i].ID, &s.Instances[i].Status)
+		}
+		return ok(utho.CloudInstances{CloudInstance: nonNil(s.Instances), Status: "success"})
+	})
+	s.handle("GET", "cloud/*", func(r *request) (int, any) {
+		i := index(s.Instances, r.params[0], instanceID)
+		if i < 0 {
+			return notFound("Cloud server")
+		}
+		s.settle(s.Instances[i].ID, &s.Instances[i].Status)
+		return ok(utho.CloudInstances{CloudInstance: s.Instances[i : i+1], Status: "success"})
+	})
+	s.handle("DELETE", "cloud/*/destroy", func(r *request) (int, any) {
+		var p utho.DeleteCloudInstanceParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		i := index(s.Instances, r.params[0], instanceID)
+		if i < 0 {
+			return notFound("Cloud server")
+		}
+		if p.Confirm != deleteServerConfirm {
+			return fail(http.StatusUnprocessableEntity, "Please confirm the deletion")
+		}
+		s.Instances = remove(s.Instances, i)
+		s.action("destroy", "Cloud", r.params[0])
+		return deleted("Cloud server")
+	})
+	s.handle("POST", "cloud/*/snapshot/create", func(r *request) (int, any) {
+		i := index(s.Instances, r.params[0], instanceID)
+		if i < 0 {
+			return notFound("Cloud server")
+		}
+		id := s.id()
+		s.Instances[i].Snapshots = append(s.Instances[i].Snapshots, utho.Snapshots{
+			ID:        id,
+			Name:      s.Instances[i].Hostname + "-" + id,
+			Size:      strconv.Itoa(s.Instances[i].Disksize),
+			CreatedAt: s.now(),
+		})
+		s.action("snapshot", "Cloud", r.params[0])
+		return ok(utho.CreateBasicResponse{Status: "success", Message: "Snapshot creation in process"})
+	})
+	s.handle("DELETE", "cloud/*/snapshot/*/delete", func(r *request) (int, any) {
+		i := index(s.Instances, r.params[0], instanceID)
+		if i < 0 {
+			return notFound("Cloud server")
+		}
+		j := index(s.Instances[i].Snapshots, r.params[1], func(sn *utho.Snapshots) string { return sn.ID })
+		if j < 0 {
+			return notFound("Snapshot")
+		}
+		s.Instances[i].Snapshots = remove(s.Instances[i].Snapshots, j)
+		s.action("snapshot_delete", "Cloud", r.params[0])
+		return deleted("Snapshot")
+	})
+	for _, toggle := range []struct{ path, value string }{{"enable", "1"}, {"disable", "0"}} {
+		toggle := toggle
+		s.handle("POST", "cloud/*/backups/"+toggle.path, func(r *request) (int, any) {
+			i := index(s.Instances, r.params[0], instanceID)
+			if i < 0 {
+				return notFound("Cloud server")
+			}
+			s.Instances[i].Features.Backups = toggle.value
+			s.action("backups_"+toggle.path, "Cloud", r.params[0])
+			return ok(utho.BasicResponse{Status: "success", Message: "Backups " + toggle.path + "d"})
+		})
+	}
+
+	// Kubernetes
+	s.handle("POST", "kubernetes/deploy", func(r *request) (int, any) {
+		var p utho.CreateKubernetesParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		if err := required("dcslug", p.Dcslug, "cluster_label", p.ClusterLabel); err != nil {
+			return badRequest(err)
+		}
+		id := s.id()
+		s.Kubernetes = append(s.Kubernetes, utho.K8s{
+			ID:             id,
+			Cloudid:        id,
+			Hostname:       p.ClusterLabel,
+			Dcslug:         p.Dcslug,
+			CreatedAt:      s.now(),
+			Status:         s.provision(id),
+			Powerstatus:    "Running",
+			WorkerCount:    strconv.Itoa(len(p.Nodepools)),
+			Dclocation:     utho.K8sDclocation{Location: p.Dcslug, Dc: p.Dcslug},
+			LoadBalancers:  []utho.K8sLoadbalancers{},
+			TargetGroups:   []utho.K8sTargetGroups{},
+			SecurityGroups: []utho.K8sSecurityGroups{},
+		})
+		s.action("deploy", "Kubernetes", id)
+		return ok(utho.CreateResponse{ID: id, Status: "success", Message: "Cluster deploy in process"})
+	})
+	s.handle("GET", "kubernetes", func(r *request) (int, any) {
+		for i := range s.Kubernetes {
+			s.settle(s.Kubernetes[i].ID, &s.Kubernetes[i].Status)
+		}
+		return ok(utho.Kubernetes{K8s: nonNil(s.Kubernetes), Status: "success"})
+	})
+	s.handle("GET", "kubernetes/*", func(r *request) (int, any) {
+		i := index(s.Kubernetes, r.params[0], clusterID)
+		if i < 0 {
+			return notFound("Cluster")
+		}
+		s.settle(s.Kubernetes[i].ID, &s.Kubernetes[i].Status)
+		return ok(utho.Kubernetes{K8s: s.Kubernetes[i : i+1], Status: "success"})
+	})
+	s.handle("DELETE", "kubernetes/*/destroy", func(r *request) (int, any) {
+		i := index(s.Kubernetes, r.params[0], clusterID)
+		if i < 0 {
+			return notFound("Cluster")
+		}
+		s.Kubernetes = remove(s.Kubernetes, i)
+		s.action("destroy", "Kubernetes", r.params[0])
+		return deleted("Cluster")
+	})
+	s.handle("POST", "kubernetes/*/loadbalancer/*", func(r *request) (int, any) {
+		i := index(s.Kubernetes, r.params[0], clusterID)
+		if i < 0 {
+			return notFound("Cluster")
+		}
+		j := index(s.Loadbalancers, r.params[1], loadbalancerID)
+		if j < 0 {
+			return notFound("Load balancer")
+		}
+		lb := s.Loadbalancers[j]
+		s.Kubernetes[i].LoadBalancers = append(s.Kubernetes[i].LoadBalancers, utho.K8sLoadbalancers{ID: lb.ID, Name: lb.Name, IP: lb.IP})
+		s.action("loadbalancer_attach", "Kubernetes", r.params[0])
+		return ok(utho.CreateResponse{ID: lb.ID, Status: "success", Message: "Load balancer attached"})
+	})
+	s.handle("DELETE", "kubernetes/*/loadbalancerpolicy/*", func(r *request) (int, any) {
+		i := index(s.Kubernetes, r.params[0], clusterID)
+		if i < 0 {
+			return notFound("Cluster")
+		}
+		j := index(s.Kubernetes[i].LoadBalancers, r.params[1], func(lb *utho.K8sLoadbalancers) string { return lb.ID })
+		if j < 0 {
+			return notFound("Load balancer")
+		}
+		s.Kubernetes[i].LoadBalancers = remove(s.Kubernetes[i].LoadBalancers, j)
+		s.action("loadbalancer_detach", "Kubernetes", r.params[0])
+		return deleted("Load balancer")
+	})
+	s.handle("POST", "kubernetes/*/securitygroup/*", func(r *request) (int, any) {
+		i := index(s.Kubernetes, r.params[0], clusterID)
+		if i < 0 {
+			return notFound("Cluster")
+		}
+		j := index(s.Firewalls, r.params[1], firewallID)
+		if j < 0 {
+			return notFound("Security group")
+		}
+		fw := s.Firewalls[j]
+		s.Kubernetes[i].SecurityGroups = append(s.Kubernetes[i].SecurityGroups, utho.K8sSecurityGroups{ID: fw.ID, Name: fw.Name})
+		s.action("securitygroup_attach", "Kubernetes", r.params[0])
+		return ok(utho.CreateResponse{ID: fw.ID, Status: "success", Message: "Security group attached"})
+	})
+	s.handle("DELETE", "kubernetes/*/securitygroup/*", func(r *request) (int, any) {
+		i := index(s.Kubernetes, r.params[0], clusterID)
+		if i < 0 {
+			return notFound("Cluster")
+		}
+		j := index(s.Kubernetes[i].SecurityGroups, r.params[1], func(sg *utho.K8sSecurityGroups) string { return sg.ID })
+		if j < 0 {
+			return notFound("Security group")
+		}
+		s.Kubernetes[i].SecurityGroups = remove(s.Kubernetes[i].SecurityGroups, j)
+		s.action("securitygroup_detach", "Kubernetes", r.params[0])
+		return deleted("Security group")
+	})
+	s.handle("POST", "kubernetes/*/targetgroup/*", func(r *request) (int, any) {
+		i := index(s.Kubernetes, r.params[0], clusterID)
+		if i < 0 {
+			return notFound("Cluster")
+		}
+		j := index(s.TargetGroups, r.params[1], targetGroupID)
+		if j < 0 {
+			return notFound("Target group")
+		}
+		tg := s.TargetGroups[j]
+		s.Kubernetes[i].TargetGroups = append(s.Kubernetes[i].TargetGroups, utho.K8sTargetGroups{ID: tg.ID, Name: tg.Name, Protocol: tg.Protocol, Port: tg.Port})
+		s.action("targetgroup_attach", "Kubernetes", r.params[0])
+		return ok(utho.CreateResponse{ID: tg.ID, Status: "success", Message: "Target group attached"})
+	})
+	s.handle("DELETE", "kubernetes/*/targetgroup/*", func(r *request) (int, any) {
+		i := index(s.Kubernetes, r.params[0], clusterID)
+		if i < 0 {
+			return notFound("Cluster")
+		}
+		j := index(s.Kubernetes[i].TargetGroups, r.params[1], func(tg *utho.K8sTargetGroups) string { return tg.ID })
+		if j < 0 {
+			return notFound("Target group")
+		}
+		s.Kubernetes[i].TargetGroups = remove(s.Kubernetes[i].TargetGroups, j)
+		s.action("targetgroup_detach", "Kubernetes", r.params[0])
+		return deleted("Target group")
+	})
+
+	// Auto scaling
+	s.handle("POST", "autoscaling", func(r *request) (int, any) {
+		var p utho.CreateAutoScalingParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		if err := required("name", p.Name, "dcslug", p.Dcslug, "planid", p.Planid, "minsize", p.Minsize, "maxsize", p.Maxsize, "desiredsize", p.Desiredsize); err != nil {
+			return badRequest(err)
+		}
+		id := s.id()
+		s.AutoScaling = append(s.AutoScaling, utho.Groups{
+			ID:                 id,
+			Userid:             s.Account.ID,
+			Name:               p.Name,
+			Dcslug:             p.Dcslug,
+			Minsize:            p.Minsize,
+			Maxsize:            p.Maxsize,
+			Desiredsize:        p.Desiredsize,
+			Planid:             p.Planid,
+			Planname:           p.Planname,
+			InstanceTemplateid: p.InstanceTemplateid,
+			Image:              p.Stackimage,
+			Status:             s.provision(id),
+			CreatedAt:          s.now(),
+			Dclocation:         utho.Dclocation{Location: p.Dcslug, Dc: p.Dcslug},
+			Vpc:                []utho.AutoScalingVpc{},
+			Loadbalancers:      []utho.AutoScalingLoadbalancers{},
+			TargetGroups:       []utho.AutoScalingTargetGroup{},
+			SecurityGroups:     []utho.SecurityGroup{},
+			Instances:          []utho.Instances{},
+			Policies:           []utho.Policy{},
+			Schedules:          []utho.Schedule{},
+		})
+		s.action("create", "AutoScaling", id)
+		n, _ := strconv.Atoi(id)
+		return ok(utho.CreateAutoScalingResponse{ID: n, Status: "success", Message: "Auto scaling group created"})
+	})
+	s.handle("GET", "autoscaling", func(r *request) (int, any) {
+		for i := range s.AutoScaling {
+			s.settle(s.AutoScaling[i].ID, &s.AutoScaling[i].Status)
+		}
+		return ok(utho.AutoScalings{Groups: nonNil(s.AutoScaling), Status: "success"})
+	})
+	s.handle("GET", "autoscaling/*", func(r *request) (int, any) {
+		i := index(s.AutoScaling, r.params[0], groupID)
+		if i < 0 {
+			return notFound("Auto scaling group")
+		}
+		s.settle(s.AutoScaling[i].ID, &s.AutoScaling[i].Status)
+		return ok(utho.AutoScalings{Groups: s.AutoScaling[i : i+1], Status: "success"})
+	})
+	s.handle("DELETE", "autoscaling/*", func(r *request) (int, any) {
+		i := index(s.AutoScaling, r.params[0], groupID)
+		if i < 0 {
+			return notFound("Auto scaling group")
+		}
+		if name := r.URL.Query().Get("name"); name != s.AutoScaling[i].Name {
+			return fail(http.StatusUnprocessableEntity, "Auto scaling group name does not match")
+		}
+		s.AutoScaling = remove(s.AutoScaling, i)
+		s.action("destroy", "AutoScaling", r.params[0])
+		return deleted("Auto scaling group")
+	})
+	s.handle("POST", "autoscaling/policy", func(r *request) (int, any) {
+		var p utho.CreateAutoScalingPolicyParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		if err := required("name", p.Name, "productid", p.Productid); err != nil {
+			return badRequest(err)
+		}
+		i := index(s.AutoScaling, p.Productid, groupID)
+		if i < 0 {
+			return notFound("Auto scaling group")
+		}
+		id := s.id()
+		s.AutoScaling[i].Policies = append(s.AutoScaling[i].Policies, utho.Policy{
+			ID:        id,
+			Userid:    s.Account.ID,
+			Product:   p.Product,
+			Productid: p.Productid,
+			Groupid:   p.Productid,
+			Name:      p.Name,
+			Type:      p.Type,
+			Adjust:    p.Adjust,
+			Period:    p.Period,
+			Cooldown:  p.Cooldown,
+			Compare:   p.Compare,
+			Value:     p.Value,
+			Status:    "Active",
+			Maxsize:   s.AutoScaling[i].Maxsize,
+			Minsize:   s.AutoScaling[i].Minsize,
+		})
+		s.action("policy_create", "AutoScaling", p.Productid)
+		return ok(utho.CreateResponse{ID: id, Status: "success", Message: "Policy created"})
+	})
+	s.handle("DELETE", "autoscaling/policy/*", func(r *request) (int, any) {
+		for i := range s.AutoScaling {
+			j := index(s.AutoScaling[i].Policies, r.params[0], func(p *utho.Policy) string { return p.ID })
+			if j >= 0 {
+				s.AutoScaling[i].Policies = remove(s.AutoScaling[i].Policies, j)
+				s.action("policy_delete", "AutoScaling", s.AutoScaling[i].ID)
+				return deleted("Policy")
+			}
+		}
+		return notFound("Policy")
+	})
+	s.handle("POST", "autoscaling/*/schedulepolicy", func(r *request) (int, any) {
+		var p utho.CreateAutoScalingScheduleParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		i := index(s.AutoScaling, r.params[0], groupID)
+		if i < 0 {
+			return notFound("Auto scaling group")
+		}
+		if err := required("name", p.Name, "desiredsize", p.Desiredsize); err != nil {
+			return badRequest(err)
+		}
+		id := s.id()
+		s.AutoScaling[i].Schedules = append(s.AutoScaling[i].Schedules, utho.Schedule{
+			ID:          id,
+			Groupid:     r.params[0],
+			Name:        p.Name,
+			Desiredsize: p.Desiredsize,
+			Recurrence:  p.Recurrence,
+			StartDate:   p.StartDate,
+			Status:      "Active",
+			Timezone:    "UTC",
+		})
+		s.action("schedule_create", "AutoScaling", r.params[0])
+		return ok(utho.CreateResponse{ID: id, Status: "success", Message: "Schedule created"})
+	})
+	s.handle("DELETE", "autoscaling/*/schedulepolicy/*", func(r *request) (int, any) {
+		i := index(s.AutoScaling, r.params[0], groupID)
+		if i < 0 {
+			return notFound("Auto scaling group")
+		}
+		j := index(s.AutoScaling[i].Schedules, r.params[1], func(sc *utho.Schedule) string { return sc.ID })
+		if j < 0 {
+			return notFound("Schedule")
+		}
+		s.AutoScaling[i].Schedules = remove(s.AutoScaling[i].Schedules, j)
+		s.action("schedule_delete", "AutoScaling", r.params[0])
+		return deleted("Schedule")
+	})
+	s.handle("POST", "autoscaling/*/loadbalancer/*", func(r *request) (int, any) {
+		i := index(s.AutoScaling, r.params[0], groupID)
+		if i < 0 {
+			return notFound("Auto scaling group")
+		}
+		j := index(s.Loadbalancers, r.params[1], loadbalancerID)
+		if j < 0 {
+			return notFound("Load balancer")
+		}
+		lb := s.Loadbalancers[j]
+		s.AutoScaling[i].Loadbalancers = append(s.AutoScaling[i].Loadbalancers, utho.AutoScalingLoadbalancers{ID: lb.ID, Name: lb.Name, IP: lb.IP})
+		s.action("loadbalancer_attach", "AutoScaling", r.params[0])
+		return ok(utho.CreateResponse{ID: lb.ID, Status: "success", Message: "Load balancer attached"})
+	})
+	s.handle("DELETE", "autoscaling/*/loadbalancerpolicy/*", func(r *request) (int, any) {
+		i := index(s.AutoScaling, r.params[0], groupID)
+		if i < 0 {
+			return notFound("Auto scaling group")
+		}
+		j := index(s.AutoScaling[i].Loadbalancers, r.params[1], func(lb *utho.AutoScalingLoadbalancers) string { return lb.ID })
+		if j < 0 {
+			return notFound("Load balancer")
+		}
+		s.AutoScaling[i].Loadbalancers = remove(s.AutoScaling[i].Loadbalancers, j)
+		s.action("loadbalancer_detach", "AutoScaling", r.params[0])
+		return deleted("Load balancer")
+	})
+	s.handle("POST", "autoscaling/*/securitygroup/*", func(r *request) (int, any) {
+		i := index(s.AutoScaling, r.params[0], groupID)
+		if i < 0 {
+			return notFound("Auto scaling group")
+		}
+		j := index(s.Firewalls, r.params[1], firewallID)
+		if j < 0 {
+			return notFound("Security group")
+		}
+		fw := s.Firewalls[j]
+		s.AutoScaling[i].SecurityGroups = append(s.AutoScaling[i].SecurityGroups, utho.SecurityGroup{ID: fw.ID, Name: fw.Name})
+		s.action("securitygroup_attach", "AutoScaling", r.params[0])
+		return ok(utho.CreateResponse{ID: fw.ID, Status: "success", Message: "Security group attached"})
+	})
+	s.handle("DELETE", "autoscaling/*/securitygroup/*", func(r *request) (int, any) {
+		i := index(s.AutoScaling, r.params[0], groupID)
+		if i < 0 {
+			return notFound("Auto scaling group")
+		}
+		j := index(s.AutoScaling[i].SecurityGroups, r.params[1], func(sg *utho.SecurityGroup) string { return sg.ID })
+		if j < 0 {
+			return notFound("Security group")
+		}
+		s.AutoScaling[i].SecurityGroups = remove(s.AutoScaling[i].SecurityGroups, j)
+		s.action("securitygroup_detach", "AutoScaling", r.params[0])
+		return deleted("Security group")
+	})
+	s.handle("POST", "autoscaling/*/targetgroup/*", func(r *request) (int, any) {
+		i := index(s.AutoScaling, r.params[0], groupID)
+		if i < 0 {
+			return notFound("Auto scaling group")
+		}
+		j := index(s.TargetGroups, r.params[1], targetGroupID)
+		if j < 0 {
+			return notFound("Target group")
+		}
+		tg := s.TargetGroups[j]
+		s.AutoScaling[i].TargetGroups = append(s.AutoScaling[i].TargetGroups, utho.AutoScalingTargetGroup{ID: tg.ID, Name: tg.Name, Protocol: tg.Protocol, Port: tg.Port})
+		s.action("targetgroup_attach", "AutoScaling", r.params[0])
+		return ok(utho.CreateResponse{ID: tg.ID, Status: "success", Message: "Target group attached"})
+	})
+	s.handle("DELETE", "autoscaling/*/targetgroup/*", func(r *request) (int, any) {
+		i := index(s.AutoScaling, r.params[0], groupID)
+		if i < 0 {
+			return notFound("Auto scaling group")
+		}
+		j := index(s.AutoScaling[i].TargetGroups, r.params[1], func(tg *utho.AutoScalingTargetGroup) string { return tg.ID })
+		if j < 0 {
+			return notFound("Target group")
+		}
+		s.AutoScaling[i].TargetGroups = remove(s.AutoScaling[i].TargetGroups, j)
+		s.action("targetgroup_detach", "AutoScaling", r.params[0])
+		return deleted("Target group")
+	})
+}
@@ -0,0 +1,387 @@
+// Package fakeapi is an in-memory stand-in for the Utho API, used to run
+// uthoctl commands in tests. Resources are kept in the utho-go types, so the
+// responses decode exactly like the ones of the real API.
+package fakeapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uthoplatforms/utho-go/utho"
+)
+
+// DefaultToken is the token accepted by a new Server.
+const DefaultToken = "fake-token"
+
+// Epoch is the time of the first timestamp handed out by a Server. Every
+// timestamp is one second after the previous one, so outputs are stable.
+var Epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Server is a fake Utho API listening on a local port. The exported fields
+// hold its state; they may be seeded before running a command and inspected
+// afterwards, but not while a request is served.
+type Server struct {
+	// URL is the base URL to pass to --api-url.
+	URL string
+	// Token is the bearer token the server accepts.
+	Token string
+	// Latency delays every response, eg: to test timeouts.
+	Latency time.Duration
+	// ProvisionReads keeps new instances, clusters, load balancers, VPCs and
+	// auto scaling groups "Pending" for that many reads, after which their
+	// status is ProvisionStatus, "Active" when empty.
+	ProvisionReads  int
+	ProvisionStatus string
+	// ActionReads keeps new actions pending for that many reads of the
+	// action list, after which their status is ActionStatus, "Success" when
+	// empty.
+	ActionReads  int
+	ActionStatus string
+
+	Account       utho.User
+	Instances     []utho.CloudInstance
+	Domains       []utho.Domain
+	Firewalls     []utho.Firewall
+	Vpcs          []utho.Vpc
+	Loadbalancers []utho.Loadbalancer
+	TargetGroups  []utho.TargetGroup
+	Kubernetes    []utho.K8s
+	AutoScaling   []utho.Groups
+	Buckets       []utho.Bucket
+	AccessKeys    []utho.AccessKey
+	Actions       []utho.Action
+
+	mu       sync.Mutex
+	srv      *httptest.Server
+	routes   []route
+	lastID   int
+	ticks    int
+	requests []string
+	// provisioning counts the reads left before a resource is provisioned.
+	provisioning map[string]int
+	// pendingActions counts the reads left before an action completes.
+	pendingActions map[string]int
+}
+
+// New starts a Server with an empty account. Close it when done.
+func New() *Server {
+	s := &Server{
+		Token: DefaultToken,
+		Account: utho.User{
+			ID:       "1",
+			Type:     "Individual",
+			Fullname: "Test User",
+			Email:    "test@example.com",
+			Currency: "INR",
+		},
+		lastID:         1000,
+		provisioning:   map[string]int{},
+		pendingActions: map[string]int{},
+	}
+	s.registerRoutes()
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL + "/v2/"
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Requests returns the requests served so far as "METHOD path" lines.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// route maps a method and a path pattern, where "*" matches any segment, to
+// a handler. The matched segments are passed as params.
+type route struct {
+	method  string
+	pattern []string
+	handle  func(r *request) (int, any)
+}
+
+type request struct {
+	*http.Request
+	params []string
+}
+
+// decode reads the JSON body into v. An empty body leaves v unchanged.
+func (r *request) decode(v any) error {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+func (s *Server) handle(method, pattern string, h func(r *request) (int, any)) {
+	s.routes = append(s.routes, route{method: method, pattern: strings.Split(pattern, "/"), handle: h})
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v2"), "/")
+	s.mu.Lock()
+	s.requests = append(s.requests, r.Method+" "+path)
+	latency := s.Latency
+	s.mu.Unlock()
+
+	select {
+	case <-time.After(latency):
+	case <-r.Context().Done():
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	actions := len(s.Actions)
+	status, body := s.dispatch(r, path)
+	if r.Method != http.MethodGet && status < 300 && len(s.Actions) > actions {
+		// Like the endpoints that report it, return the action started.
+		body = withField(body, "actionid", s.Actions[len(s.Actions)-1].ID)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func (s *Server) dispatch(r *http.Request, path string) (int, any) {
+	if r.Header.Get("Authorization") != "Bearer "+s.Token {
+		return fail(http.StatusUnauthorized, "Invalid token")
+	}
+
+	segments := strings.Split(path, "/")
+	methodAllowed := false
+	for _, rt := range s.routes {
+		params, ok := match(rt.pattern, segments)
+		if !ok {
+			continue
+		}
+		if rt.method != r.Method {
+			methodAllowed = true
+			continue
+		}
+		return rt.handle(&request{Request: r, params: params})
+	}
+	if methodAllowed {
+		return fail(http.StatusMethodNotAllowed, "Method not allowed")
+	}
+	return fail(http.StatusNotFound, "Unknown endpoint "+path)
+}
+
+func match(pattern, segments []string) ([]string, bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+	var params []string
+	for i, p := range pattern {
+		switch {
+		case p == "*":
+			params = append(params, segments[i])
+		case p != segments[i]:
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// errorBody is the body of failed requests. It carries the status and
+// message of the Utho responses and the errors list read by utho-go.
+type errorBody struct {
+	Status  string       `json:"status"`
+	Message string       `json:"message"`
+	Errors  []utho.Error `json:"errors"`
+}
+
+func fail(status int, message string) (int, any) {
+	return status, errorBody{
+		Status:  "error",
+		Message: message,
+		Errors:  []utho.Error{{Message: message, Code: strconv.Itoa(status)}},
+	}
+}
+
+func notFound(what string) (int, any) {
+	return fail(http.StatusNotFound, what+" not found")
+}
+
+func badRequest(err error) (int, any) {
+	return fail(http.StatusUnprocessableEntity, err.Error())
+}
+
+func ok(v any) (int, any) {
+	return http.StatusOK, v
+}
+
+func deleted(what string) (int, any) {
+	return ok(utho.DeleteResponse{Status: "success", Message: what + " deleted"})
+}
+
+// required returns an error naming the first empty value of the name, value
+// pairs.
+func required(pairs ...string) error {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if strings.TrimSpace(pairs[i+1]) == "" {
+			return fmt.Errorf("%s is required", pairs[i])
+		}
+	}
+	return nil
+}
+
+// id returns a new resource ID.
+func (s *Server) id() string {
+	s.lastID++
+	return strconv.Itoa(s.lastID)
+}
+
+// now returns the next timestamp in the format of the Utho API.
+func (s *Server) now() string {
+	t := Epoch.Add(time.Duration(s.ticks) * time.Second)
+	s.ticks++
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// action records an action on a resource, completed unless ActionReads is
+// set.
+func (s *Server) action(name, resourceType, resourceID string) {
+	a := utho.Action{
+		Userid:       s.Account.ID,
+		ID:           s.id(),
+		Action:       name,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		StartedAt:    s.now(),
+		Process:      "0",
+		Status:       "Pending",
+	}
+	if s.ActionReads > 0 {
+		s.pendingActions[a.ID] = s.ActionReads
+	} else {
+		s.completeAction(&a)
+	}
+	s.Actions = append(s.Actions, a)
+}
+
+// progressActions counts a read of the action list, advancing the pending
+// actions.
+func (s *Server) progressActions() {
+	for i := range s.Actions {
+		a := &s.Actions[i]
+		left, ok := s.pendingActions[a.ID]
+		switch {
+		case !ok:
+		case left > 1:
+			s.pendingActions[a.ID] = left - 1
+			a.Process = strconv.Itoa(100 * (s.ActionReads - left + 1) / s.ActionReads)
+			a.Status = "Running"
+		default:
+			delete(s.pendingActions, a.ID)
+			s.completeAction(a)
+		}
+	}
+}
+
+func (s *Server) completeAction(a *utho.Action) {
+	a.CompletedAt = a.StartedAt
+	a.Process = "100"
+	a.Status = s.ActionStatus
+	if a.Status == "" {
+		a.Status = "Success"
+	}
+}
+
+// withField returns the JSON object v with key set to value.
+func withField(v any, key string, value any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var fields map[string]any
+	if json.Unmarshal(data, &fields) != nil {
+		return v
+	}
+	fields[key] = value
+	return fields
+}
+
+// provision returns the status of the new resource id, and starts its
+// provisioning if ProvisionReads is set.
+func (s *Server) provision(id string) string {
+	if s.ProvisionReads <= 0 {
+		return s.provisionedStatus()
+	}
+	s.provisioning[id] = s.ProvisionReads
+	return "Pending"
+}
+
+// settle counts a read of the resource id and updates its status once it is
+// provisioned.
+func (s *Server) settle(id string, status *string) {
+	left, ok := s.provisioning[id]
+	if !ok {
+		return
+	}
+	if left > 1 {
+		s.provisioning[id] = left - 1
+		return
+	}
+	delete(s.provisioning, id)
+	*status = s.provisionedStatus()
+}
+
+func (s *Server) provisionedStatus() string {
+	if s.ProvisionStatus == "" {
+		return "Active"
+	}
+	return s.ProvisionStatus
+}
+
+// index returns the position of the first element for which key returns id,
+// or -1.
+func index[T any](list []T, id string, key func(*T) string) int {
+	for i := range list {
+		if key(&list[i]) == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func remove[T any](list []T, i int) []T {
+	return append(list[:i], list[i+1:]...)
+}
+
+// nonNil returns list, or an empty list instead of nil so it is encoded as
+// [] like the API does.
+func nonNil[T any](list []T) []T {
+	if list == nil {
+		return []T{}
+	}
+	return list
+}
+
+func (s *Server) registerRoutes() {
+	s.handle("GET", "account/info", func(r *request) (int, any) {
+		return ok(utho.Account{User: s.Account, Status: "success"})
+	})
+	s.handle("GET", "actions", func(r *request) (int, any) {
+		s.progressActions()
+		return ok(utho.Actions{Actions: nonNil(s.Actions), Status: "success"})
+	})
+
+	s.registerCompute()
+	s.registerNetworking()
+	s.registerStorage()
+}
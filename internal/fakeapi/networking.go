@@ -0,0 +1,550 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/uthoplatforms/utho-go/utho"
+)
+
+func firewallID(f *utho.Firewall) string          { return f.ID }
+func vpcID(v *utho.Vpc) string                    { return v.ID }
+func loadbalancerID(lb *utho.Loadbalancer) string { return lb.ID }
+func targetGroupID(tg *utho.TargetGroup) string   { return tg.ID }
+func domainName(d *utho.Domain) string            { return d.Domain }
+
+func (s *Server) registerNetworking() {
+	// Firewalls
+	s.handle("POST", "firewall/create", func(r *request) (int, any) {
+		var p utho.CreateFirewallParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		if err := required("name", p.Name); err != nil {
+			return badRequest(err)
+		}
+		id := s.id()
+		s.Firewalls = append(s.Firewalls, utho.Firewall{
+			ID:           id,
+			Name:         p.Name,
+			CreatedAt:    s.now(),
+			Rulecount:    "0",
+			Serverscount: "0",
+			Rules:        []utho.FirewallRule{},
+		})
+		s.action("create", "Firewall", id)
+		return ok(utho.CreateFirewallResponse{ID: id, Status: "success", Message: "Firewall created"})
+	})
+	s.handle("GET", "firewall", func(r *request) (int, any) {
+		return ok(utho.Firewalls{Firewalls: nonNil(s.Firewalls), Status: "success"})
+	})
+	s.handle("GET", "firewall/*", func(r *request) (int, any) {
+		i := index(s.Firewalls, r.params[0], firewallID)
+		if i < 0 {
+			return notFound("Firewall")
+		}
+		return ok(utho.Firewalls{Firewalls: s.Firewalls[i : i+1], Status: "success"})
+	})
+	s.handle("DELETE", "firewall/*/destroy", func(r *request) (int, any) {
+		i := index(s.Firewalls, r.params[0], firewallID)
+		if i < 0 {
+			return notFound("Firewall")
+		}
+		s.Firewalls = remove(s.Firewalls, i)
+		s.action("destroy", "Firewall", r.params[0])
+		return deleted("Firewall")
+	})
+	s.handle("POST", "firewall/*/rule/add", func(r *request) (int, any) {
+		var p utho.CreateFirewallRuleParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		i := index(s.Firewalls, r.params[0], firewallID)
+		if i < 0 {
+			return notFound("Firewall")
+		}
+		if err := required("type", p.Type, "protocol", p.Protocol, "port", p.Port, "addresses", p.Addresses); err != nil {
+			return badRequest(err)
+		}
+		id := s.id()
+		fw := &s.Firewalls[i]
+		fw.Rules = append(fw.Rules, utho.FirewallRule{
+			ID:         id,
+			Firewallid: fw.ID,
+			Type:       p.Type,
+			Service:    p.Service,
+			Protocol:   p.Protocol,
+			Port:       p.Port,
+			Addresses:  p.Addresses,
+		})
+		fw.Rulecount = strconv.Itoa(len(fw.Rules))
+		s.action("rule_add", "Firewall", fw.ID)
+		return ok(utho.CreateResponse{ID: id, Status: "success", Message: "Firewall rule added"})
+	})
+	s.handle("DELETE", "firewall/*/rule/*/delete", func(r *request) (int, any) {
+		i := index(s.Firewalls, r.params[0], firewallID)
+		if i < 0 {
+			return notFound("Firewall")
+		}
+		fw := &s.Firewalls[i]
+		j := index(fw.Rules, r.params[1], func(rule *utho.FirewallRule) string { return rule.ID })
+		if j < 0 {
+			return notFound("Firewall rule")
+		}
+		fw.Rules = remove(fw.Rules, j)
+		fw.Rulecount = strconv.Itoa(len(fw.Rules))
+		s.action("rule_delete", "Firewall", fw.ID)
+		return deleted("Firewall rule")
+	})
+
+	// VPCs
+	s.handle("POST", "vpc/create", func(r *request) (int, any) {
+		var p utho.CreateVpcParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		if err := required("dcslug", p.Dcslug, "name", p.Name, "planid", p.Planid, "network", p.Network, "size", p.Size); err != nil {
+			return badRequest(err)
+		}
+		size, err := strconv.Atoi(p.Size)
+		if err != nil || size < 16 || size > 30 {
+			return badRequest(fmt.Errorf("invalid size %q", p.Size))
+		}
+		id := s.id()
+		total := 1<<(32-size) - 2
+		s.Vpcs = append(s.Vpcs, utho.Vpc{
+			ID:         id,
+			Total:      total,
+			Available:  total,
+			Network:    p.Network,
+			Name:       p.Name,
+			Size:       p.Size,
+			Dcslug:     p.Dcslug,
+			Dclocation: utho.VpcDclocation{Location: p.Dcslug},
+			IsDefault:  "0",
+			Resources:  []utho.VpcResources{},
+			Status:     s.provision(id),
+		})
+		s.action("create", "VPC", id)
+		return ok(utho.CreateResponse{ID: id, Status: "success", Message: "VPC created"})
+	})
+	s.handle("GET", "vpc", func(r *request) (int, any) {
+		for i := range s.Vpcs {
+			s.settle(s.Vpcs[i].ID, &s.Vpcs[i].Status)
+		}
+		return ok(utho.Vpcs{Vpc: nonNil(s.Vpcs), Status: "success"})
+	})
+	s.handle("DELETE", "vpc/*/destroy", func(r *request) (int, any) {
+		i := index(s.Vpcs, r.params[0], vpcID)
+		if i < 0 {
+			return notFound("VPC")
+		}
+		s.Vpcs = remove(s.Vpcs, i)
+		s.action("destroy", "VPC", r.params[0])
+		return deleted("VPC")
+	})
+
+	// Load balancers
+	s.handle("POST", "loadbalancer", func(r *request) (int, any) {
+		var p utho.CreateLoadbalancerParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		if err := required("dcslug", p.Dcslug, "name", p.Name); err != nil {
+			return badRequest(err)
+		}
+		lbType := p.Type
+		if lbType == "" {
+			lbType = "application"
+		}
+		if lbType != "application" && lbType != "network" {
+			return badRequest(fmt.Errorf("invalid type %q", p.Type))
+		}
+		id := s.id()
+		s.Loadbalancers = append(s.Loadbalancers, utho.Loadbalancer{
+			ID:           id,
+			Userid:       s.Account.ID,
+			IP:           fmt.Sprintf("198.51.100.%d", len(s.Loadbalancers)+10),
+			Name:         p.Name,
+			Algorithm:    "roundrobin",
+			Type:         lbType,
+			City:         p.Dcslug,
+			Backendcount: "0",
+			CreatedAt:    s.now(),
+			Status:       s.provision(id),
+			Backends:     []utho.Backends{},
+			Rules:        []utho.Rules{},
+			Acls:         []utho.ACLs{},
+			Routes:       []utho.Routes{},
+			Frontends:    []utho.Frontends{},
+		})
+		s.action("create", "Loadbalancer", id)
+		return ok(utho.CreateLoadbalancerResponse{ID: id, Status: "success", Message: "Load balancer created"})
+	})
+	s.handle("GET", "loadbalancer", func(r *request) (int, any) {
+		for i := range s.Loadbalancers {
+			s.settle(s.Loadbalancers[i].ID, &s.Loadbalancers[i].Status)
+		}
+		return ok(utho.Loadbalancers{Loadbalancers: nonNil(s.Loadbalancers), Status: "success"})
+	})
+	s.handle("GET", "loadbalancer/*", func(r *request) (int, any) {
+		i := index(s.Loadbalancers, r.params[0], loadbalancerID)
+		if i < 0 {
+			return notFound("Load balancer")
+		}
+		s.settle(s.Loadbalancers[i].ID, &s.Loadbalancers[i].Status)
+		return ok(utho.Loadbalancers{Loadbalancers: s.Loadbalancers[i : i+1], Status: "success"})
+	})
+	s.handle("DELETE", "loadbalancer/*", func(r *request) (int, any) {
+		i := index(s.Loadbalancers, r.params[0], loadbalancerID)
+		if i < 0 {
+			return notFound("Load balancer")
+		}
+		s.Loadbalancers = remove(s.Loadbalancers, i)
+		s.action("destroy", "Loadbalancer", r.params[0])
+		return deleted("Load balancer")
+	})
+	s.handle("POST", "loadbalancer/*/frontend", func(r *request) (int, any) {
+		var p utho.CreateLoadbalancerFrontendParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		i := index(s.Loadbalancers, r.params[0], loadbalancerID)
+		if i < 0 {
+			return notFound("Load balancer")
+		}
+		if err := required("name", p.Name, "proto", p.Proto, "port", p.Port); err != nil {
+			return badRequest(err)
+		}
+		id := s.id()
+		at := s.now()
+		lb := &s.Loadbalancers[i]
+		lb.Frontends = append(lb.Frontends, utho.Frontends{
+			ID:            id,
+			Name:          p.Name,
+			Algorithm:     p.Algorithm,
+			Cookie:        p.Cookie,
+			Redirecthttps: p.Redirecthttps,
+			CertificateID: p.CertificateID,
+			Port:          p.Port,
+			Proto:         p.Proto,
+			CreatedAt:     at,
+			UpdatedAt:     at,
+			Acls:          []utho.ACLs{},
+			Routes:        []utho.FrontendRoutes{},
+		})
+		s.action("frontend_add", "Loadbalancer", lb.ID)
+		return ok(utho.CreateResponse{ID: id, Status: "success", Message: "Frontend added"})
+	})
+	s.handle("POST", "loadbalancer/*/acl", func(r *request) (int, any) {
+		var p utho.CreateLoadbalancerACLParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		i := index(s.Loadbalancers, r.params[0], loadbalancerID)
+		if i < 0 {
+			return notFound("Load balancer")
+		}
+		if err := required("name", p.Name, "conditionType", p.ConditionType, "frontend_id", p.FrontendID); err != nil {
+			return badRequest(err)
+		}
+		lb := &s.Loadbalancers[i]
+		if index(lb.Frontends, p.FrontendID, func(f *utho.Frontends) string { return f.ID }) < 0 {
+			return notFound("Frontend")
+		}
+		id := s.id()
+		lb.Acls = append(lb.Acls, utho.ACLs{ID: id, Name: p.Name, ACLCondition: p.ConditionType, Value: p.Value})
+		s.action("acl_add", "Loadbalancer", lb.ID)
+		return ok(utho.CreateResponse{ID: id, Status: "success", Message: "ACL added"})
+	})
+	s.handle("POST", "loadbalancer/*/backend", func(r *request) (int, any) {
+		var p utho.CreateLoadbalancerBackendParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		i := index(s.Loadbalancers, r.params[0], loadbalancerID)
+		if i < 0 {
+			return notFound("Load balancer")
+		}
+		if err := required("frontend_id", p.FrontendID, "cloudid", p.Cloudid); err != nil {
+			return badRequest(err)
+		}
+		lb := &s.Loadbalancers[i]
+		if index(lb.Frontends, p.FrontendID, func(f *utho.Frontends) string { return f.ID }) < 0 {
+			return notFound("Frontend")
+		}
+		j := index(s.Instances, p.Cloudid, instanceID)
+		if j < 0 {
+			return notFound("Cloud server")
+		}
+		vm := s.Instances[j]
+		id := s.id()
+		lb.Backends = append(lb.Backends, utho.Backends{
+			ID:      id,
+			Lb:      lb.ID,
+			IP:      vm.IP,
+			Cloudid: vm.ID,
+			Name:    vm.Hostname,
+			RAM:     vm.RAM,
+			CPU:     vm.CPU,
+			Disk:    strconv.Itoa(vm.Disksize),
+			City:    vm.Dclocation.Location,
+		})
+		lb.Backendcount = strconv.Itoa(len(lb.Backends))
+		s.action("backend_add", "Loadbalancer", lb.ID)
+		return ok(utho.CreateResponse{ID: id, Status: "success", Message: "Backend added"})
+	})
+	s.handle("POST", "loadbalancer/*/route", func(r *request) (int, any) {
+		var p utho.CreateLoadbalancerRouteParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		i := index(s.Loadbalancers, r.params[0], loadbalancerID)
+		if i < 0 {
+			return notFound("Load balancer")
+		}
+		if err := required("frontend_id", p.FrontendID, "acl_id", p.ACLID); err != nil {
+			return badRequest(err)
+		}
+		lb := &s.Loadbalancers[i]
+		a := index(lb.Acls, p.ACLID, func(acl *utho.ACLs) string { return acl.ID })
+		if a < 0 {
+			return notFound("ACL")
+		}
+		id := s.id()
+		lb.Routes = append(lb.Routes, utho.Routes{
+			ID:               id,
+			ACLID:            p.ACLID,
+			ACLName:          lb.Acls[a].Name,
+			RoutingCondition: p.RouteCondition,
+		})
+		s.action("route_add", "Loadbalancer", lb.ID)
+		return ok(utho.CreateResponse{ID: id, Status: "success", Message: "Route added"})
+	})
+	s.handle("DELETE", "loadbalancer/*/frontend/*", func(r *request) (int, any) {
+		return s.deleteLoadbalancerPart(r, "Frontend", func(lb *utho.Loadbalancer) bool {
+			j := index(lb.Frontends, r.params[1], func(f *utho.Frontends) string { return f.ID })
+			if j >= 0 {
+				lb.Frontends = remove(lb.Frontends, j)
+			}
+			return j >= 0
+		})
+	})
+	s.handle("DELETE", "loadbalancer/*/acl/*", func(r *request) (int, any) {
+		return s.deleteLoadbalancerPart(r, "ACL", func(lb *utho.Loadbalancer) bool {
+			j := index(lb.Acls, r.params[1], func(acl *utho.ACLs) string { return acl.ID })
+			if j >= 0 {
+				lb.Acls = remove(lb.Acls, j)
+			}
+			return j >= 0
+		})
+	})
+	s.handle("DELETE", "loadbalancer/*/backend/*", func(r *request) (int, any) {
+		return s.deleteLoadbalancerPart(r, "Backend", func(lb *utho.Loadbalancer) bool {
+			j := index(lb.Backends, r.params[1], func(b *utho.Backends) string { return b.ID })
+			if j >= 0 {
+				lb.Backends = remove(lb.Backends, j)
+				lb.Backendcount = strconv.Itoa(len(lb.Backends))
+			}
+			return j >= 0
+		})
+	})
+	s.handle("DELETE", "loadbalancer/*/route/*", func(r *request) (int, any) {
+		return s.deleteLoadbalancerPart(r, "Route", func(lb *utho.Loadbalancer) bool {
+			j := index(lb.Routes, r.params[1], func(rt *utho.Routes) string { return rt.ID })
+			if j >= 0 {
+				lb.Routes = remove(lb.Routes, j)
+			}
+			return j >= 0
+		})
+	})
+
+	// Target groups
+	s.handle("POST", "targetgroup", func(r *request) (int, any) {
+		var p utho.CreateTargetGroupParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		if err := required("name", p.Name, "protocol", p.Protocol, "port", p.Port); err != nil {
+			return badRequest(err)
+		}
+		id := s.id()
+		at := s.now()
+		s.TargetGroups = append(s.TargetGroups, utho.TargetGroup{
+			ID:                  id,
+			Name:                p.Name,
+			Port:                p.Port,
+			Protocol:            p.Protocol,
+			HealthCheckPath:     p.HealthCheckPath,
+			HealthCheckInterval: p.HealthCheckInterval,
+			HealthCheckProtocol: p.HealthCheckProtocol,
+			HealthCheckTimeout:  p.HealthCheckTimeout,
+			HealthyThreshold:    p.HealthyThreshold,
+			UnhealthyThreshold:  p.UnhealthyThreshold,
+			CreatedAt:           at,
+			UpdatedAt:           at,
+			Targets:             []utho.Target{},
+		})
+		s.action("create", "TargetGroup", id)
+		return ok(utho.CreateResponse{ID: id, Status: "success", Message: "Target group created"})
+	})
+	s.handle("GET", "targetgroup", func(r *request) (int, any) {
+		return ok(utho.TargetGroups{Targetgroups: nonNil(s.TargetGroups), Status: "success"})
+	})
+	s.handle("DELETE", "targetgroup/*", func(r *request) (int, any) {
+		i := index(s.TargetGroups, r.params[0], targetGroupID)
+		if i < 0 {
+			return notFound("Target group")
+		}
+		if name := r.URL.Query().Get("name"); name != s.TargetGroups[i].Name {
+			return fail(http.StatusUnprocessableEntity, "Target group name does not match")
+		}
+		s.TargetGroups = remove(s.TargetGroups, i)
+		s.action("destroy", "TargetGroup", r.params[0])
+		return deleted("Target group")
+	})
+	s.handle("POST", "targetgroup/*/target", func(r *request) (int, any) {
+		var p utho.CreateTargetGroupTargetParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		i := index(s.TargetGroups, r.params[0], targetGroupID)
+		if i < 0 {
+			return notFound("Target group")
+		}
+		if err := required("backend_protocol", p.BackendProtocol, "backend_port", p.BackendPort, "ip", p.IP); err != nil {
+			return badRequest(err)
+		}
+		id := s.id()
+		tg := &s.TargetGroups[i]
+		tg.Targets = append(tg.Targets, utho.Target{
+			ID:              id,
+			IP:              p.IP,
+			Cloudid:         p.Cloudid,
+			Status:          "Active",
+			BackendPort:     p.BackendPort,
+			BackendProtocol: p.BackendProtocol,
+			TargetgroupID:   tg.ID,
+		})
+		s.action("target_add", "TargetGroup", tg.ID)
+		return ok(utho.CreateResponse{ID: id, Status: "success", Message: "Target added"})
+	})
+	s.handle("DELETE", "targetgroup/*/target/*", func(r *request) (int, any) {
+		i := index(s.TargetGroups, r.params[0], targetGroupID)
+		if i < 0 {
+			return notFound("Target group")
+		}
+		tg := &s.TargetGroups[i]
+		j := index(tg.Targets, r.params[1], func(t *utho.Target) string { return t.ID })
+		if j < 0 {
+			return notFound("Target")
+		}
+		tg.Targets = remove(tg.Targets, j)
+		s.action("target_delete", "TargetGroup", tg.ID)
+		return deleted("Target")
+	})
+
+	// DNS
+	s.handle("POST", "dns/adddomain", func(r *request) (int, any) {
+		var p utho.CreateDomainParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		if err := required("domain", p.Domain); err != nil {
+			return badRequest(err)
+		}
+		if index(s.Domains, p.Domain, domainName) >= 0 {
+			return fail(http.StatusConflict, "Domain already exists")
+		}
+		s.Domains = append(s.Domains, utho.Domain{
+			Domain:         p.Domain,
+			Status:         "Active",
+			Nspoint:        "no",
+			CreatedAt:      s.now(),
+			DnsrecordCount: "0",
+			Records:        []utho.DnsRecord{},
+		})
+		s.action("create", "DNS", p.Domain)
+		return ok(utho.BasicResponse{Status: "success", Message: "Domain added"})
+	})
+	s.handle("GET", "dns", func(r *request) (int, any) {
+		return ok(utho.DnsDomains{Domains: nonNil(s.Domains), Status: "success"})
+	})
+	s.handle("GET", "dns/*", func(r *request) (int, any) {
+		i := index(s.Domains, r.params[0], domainName)
+		if i < 0 {
+			return notFound("Domain")
+		}
+		return ok(utho.DnsDomains{Domains: s.Domains[i : i+1], Status: "success"})
+	})
+	s.handle("DELETE", "dns/*/delete", func(r *request) (int, any) {
+		i := index(s.Domains, r.params[0], domainName)
+		if i < 0 {
+			return notFound("Domain")
+		}
+		s.Domains = remove(s.Domains, i)
+		s.action("destroy", "DNS", r.params[0])
+		return deleted("Domain")
+	})
+	s.handle("POST", "dns/*/record/add", func(r *request) (int, any) {
+		var p utho.CreateDnsRecordParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		i := index(s.Domains, r.params[0], domainName)
+		if i < 0 {
+			return notFound("Domain")
+		}
+		if err := required("type", p.Type, "hostname", p.Hostname, "value", p.Value); err != nil {
+			return badRequest(err)
+		}
+		ttl := p.TTL
+		if ttl == "" {
+			ttl = "1800"
+		}
+		id := s.id()
+		d := &s.Domains[i]
+		d.Records = append(d.Records, utho.DnsRecord{
+			ID:       id,
+			Hostname: p.Hostname,
+			Type:     p.Type,
+			Value:    p.Value,
+			TTL:      ttl,
+			Priority: p.Priority,
+		})
+		d.DnsrecordCount = strconv.Itoa(len(d.Records))
+		s.action("record_add", "DNS", d.Domain)
+		return ok(utho.CreateResponse{ID: id, Status: "success", Message: "Record added"})
+	})
+	s.handle("DELETE", "dns/*/record/*/delete", func(r *request) (int, any) {
+		i := index(s.Domains, r.params[0], domainName)
+		if i < 0 {
+			return notFound("Domain")
+		}
+		d := &s.Domains[i]
+		j := index(d.Records, r.params[1], func(rec *utho.DnsRecord) string { return rec.ID })
+		if j < 0 {
+			return notFound("Record")
+		}
+		d.Records = remove(d.Records, j)
+		d.DnsrecordCount = strconv.Itoa(len(d.Records))
+		s.action("record_delete", "DNS", d.Domain)
+		return deleted("Record")
+	})
+}
+
+// deleteLoadbalancerPart runs del on the load balancer of the first param,
+// which reports whether the part named by the second param was found.
+func (s *Server) deleteLoadbalancerPart(r *request, what string, del func(*utho.Loadbalancer) bool) (int, any) {
+	i := index(s.Loadbalancers, r.params[0], loadbalancerID)
+	if i < 0 {
+		return notFound("Load balancer")
+	}
+	if !del(&s.Loadbalancers[i]) {
+		return notFound(what)
+	}
+	s.action(strings.ToLower(what)+"_delete", "Loadbalancer", r.params[0])
+	return deleted(what)
+}
@@ -0,0 +1,98 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/uthoplatforms/utho-go/utho"
+)
+
+func (s *Server) registerStorage() {
+	s.handle("POST", "objectstorage/bucket/create", func(r *request) (int, any) {
+		var p utho.CreateBucketParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		if err := required("dcslug", p.Dcslug, "name", p.Name, "size", p.Size); err != nil {
+			return badRequest(err)
+		}
+		if s.bucket(p.Dcslug, p.Name) >= 0 {
+			return fail(http.StatusConflict, "Bucket already exists")
+		}
+		s.Buckets = append(s.Buckets, utho.Bucket{
+			Name:        p.Name,
+			Access:      "private",
+			Dcslug:      p.Dcslug,
+			Size:        p.Size,
+			Status:      "Active",
+			CreatedAt:   s.now(),
+			ObjectCount: "0",
+			CurrentSize: "0",
+			Permissions: []utho.Permissions{},
+			Dclocation:  utho.BucketDclocation{Location: p.Dcslug, Dc: p.Dcslug},
+		})
+		s.action("create", "ObjectStorage", p.Name)
+		return ok(utho.CreateResponse{ID: p.Name, Status: "success", Message: "Bucket created"})
+	})
+	s.handle("GET", "objectstorage/*/bucket", func(r *request) (int, any) {
+		buckets := []utho.Bucket{}
+		for _, b := range s.Buckets {
+			if b.Dcslug == r.params[0] {
+				buckets = append(buckets, b)
+			}
+		}
+		return ok(utho.Buckets{Buckets: buckets, Status: "success"})
+	})
+	s.handle("DELETE", "objectstorage/*/bucket/*/delete", func(r *request) (int, any) {
+		i := s.bucket(r.params[0], r.params[1])
+		if i < 0 {
+			return notFound("Bucket")
+		}
+		s.Buckets = remove(s.Buckets, i)
+		s.action("destroy", "ObjectStorage", r.params[1])
+		return deleted("Bucket")
+	})
+	s.handle("POST", "objectstorage/*/accesskey/create", func(r *request) (int, any) {
+		var p utho.CreateAccessKeyParams
+		if err := r.decode(&p); err != nil {
+			return badRequest(err)
+		}
+		if err := required("accesskey", p.AccesskeyName); err != nil {
+			return badRequest(err)
+		}
+		key := fmt.Sprintf("FAKEKEY%s", s.id())
+		s.AccessKeys = append(s.AccessKeys, utho.AccessKey{
+			Name:      p.AccesskeyName,
+			Accesskey: key,
+			Dcslug:    r.params[0],
+			Status:    "Active",
+			CreatedAt: s.now(),
+		})
+		s.action("accesskey_create", "ObjectStorage", key)
+		return ok(utho.CreateAccessKeyResponse{
+			Status:    "success",
+			Message:   "Access key created",
+			Accesskey: key,
+			Secretkey: "fake-secret",
+		})
+	})
+	s.handle("GET", "objectstorage/*/accesskeys", func(r *request) (int, any) {
+		keys := []utho.AccessKey{}
+		for _, k := range s.AccessKeys {
+			if k.Dcslug == r.params[0] {
+				keys = append(keys, k)
+			}
+		}
+		return ok(utho.AccessKeys{AccessKeys: keys, Status: "success"})
+	})
+}
+
+// bucket returns the position of the named bucket of dcslug, or -1.
+func (s *Server) bucket(dcslug, name string) int {
+	for i, b := range s.Buckets {
+		if b.Dcslug == dcslug && b.Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,231 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/uthoplatforms/utho-cli/helper"
+	"github.com/uthoplatforms/utho-go/utho"
+	"gopkg.in/yaml.v3"
+)
+
+// ExportOptions select the resources of the account Export describes.
+type ExportOptions struct {
+	// Kinds are the kinds of resources to export, all when empty. The
+	// resources that belong to those of a kind, such as the rules of a
+	// firewall, are exported with them, and those a kind belongs to too.
+	Kinds []string
+	// Dcslug keeps the resources of one location, and those without a
+	// location, such as firewalls and domains.
+	Dcslug string
+}
+
+// unsafeName matches what generated names replace with a dash.
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9._]+`)
+
+// Export returns a manifest describing the resources of the account, with
+// the references between them by name. Resources are ordered by kind, then
+// by name, so that exporting the same account twice gives the same
+// manifest. Secrets are left out.
+func Export(client utho.Client, opts ExportOptions) (*Manifest, error) {
+	selected, err := selectKinds(opts.Kinds)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{}
+	// names are the names given to the exported resources, by kind and ID,
+	// and parents the IDs of the exported resources of the kinds others
+	// belong to.
+	names := map[string]map[string]string{}
+	parents := map[string][]string{}
+	for _, name := range kindOrder {
+		if !selected[name] {
+			continue
+		}
+		k := kinds[name]
+		names[name] = map[string]string{}
+
+		scopes := []string{""}
+		if k.parent != "" {
+			scopes = parents[k.refs[k.parent]]
+		}
+		for _, parent := range scopes {
+			live, err := k.list(client, parent)
+			if err != nil {
+				return nil, fmt.Errorf("listing %ss: %w", name, err)
+			}
+			var exported []*Resource
+			for _, l := range live {
+				if dcslug, ok := l.Fields["dcslug"]; ok && opts.Dcslug != "" && dcslug != opts.Dcslug {
+					continue
+				}
+				r := &Resource{Kind: name, Name: l.Name, Fields: map[string]string{}, id: l.ID}
+				if r.Name == "" {
+					r.Name = k.generateName(l.Fields, names)
+				}
+				for _, field := range k.fields {
+					value := l.Fields[field]
+					if value == "" || contains(k.secrets, field) {
+						continue
+					}
+					if ref, ok := k.refs[field]; ok {
+						value = refNames(value, names[ref])
+					}
+					r.Fields[field] = value
+				}
+				exported = append(exported, r)
+			}
+
+			sort.SliceStable(exported, func(i, j int) bool {
+				if exported[i].Name != exported[j].Name {
+					return exported[i].Name < exported[j].Name
+				}
+				return lessID(exported[i].id, exported[j].id)
+			})
+			// Names are unique among the resources of a kind with the same
+			// parent.
+			seen := map[string]int{}
+			for _, r := range exported {
+				if seen[r.Name]++; seen[r.Name] > 1 {
+					r.Name += "-" + strconv.Itoa(seen[r.Name])
+				}
+				names[name][r.id] = r.Name
+				parents[name] = append(parents[name], r.id)
+				m.Resources = append(m.Resources, r)
+			}
+		}
+	}
+	return m, nil
+}
+
+// selectKinds returns the kinds to export for kinds, all when empty, with
+// those of the resources that belong to them and those they belong to.
+func selectKinds(names []string) (map[string]bool, error) {
+	selected := map[string]bool{}
+	for _, name := range names {
+		if _, ok := kinds[name]; !ok {
+			return nil, helper.UsageError(fmt.Errorf("unknown kind %q, available: %s", name, strings.Join(Kinds(), ", ")))
+		}
+		selected[name] = true
+	}
+	if len(selected) == 0 {
+		for _, name := range kindOrder {
+			selected[name] = true
+		}
+		return selected, nil
+	}
+
+	// Kinds come after those they belong to.
+	for _, name := range kindOrder {
+		if k := kinds[name]; k.parent != "" && selected[k.refs[k.parent]] {
+			selected[name] = true
+		}
+	}
+	for i := len(kindOrder) - 1; i >= 0; i-- {
+		if k := kinds[kindOrder[i]]; k.parent != "" && selected[kindOrder[i]] {
+			selected[k.refs[k.parent]] = true
+		}
+	}
+	return selected, nil
+}
+
+// generateName names a resource of a kind without names in the API after
+// its key fields, eg: A-www for a DNS record.
+func (k *kind) generateName(fields map[string]string, names map[string]map[string]string) string {
+	var parts []string
+	for _, field := range k.key {
+		value := fields[field]
+		if ref, ok := k.refs[field]; ok {
+			value = refNames(value, names[ref])
+		}
+		if value = strings.Trim(unsafeName.ReplaceAllString(value, "-"), "-"); value != "" {
+			parts = append(parts, value)
+		}
+	}
+	if len(parts) == 0 {
+		return "unnamed"
+	}
+	return strings.Join(parts, "-")
+}
+
+// refNames replaces the IDs of a reference field by the names of the
+// exported resources, keeping the IDs of the others.
+func refNames(value string, names map[string]string) string {
+	ids := strings.Split(value, ",")
+	for i, id := range ids {
+		if name, ok := names[id]; ok {
+			ids[i] = name
+		}
+	}
+	return strings.Join(ids, ",")
+}
+
+// lessID orders numeric IDs numerically, and others as strings.
+func lessID(a, b string) bool {
+	if len(a) != len(b) {
+		if _, err := strconv.Atoi(a + b); err == nil {
+			return len(a) < len(b)
+		}
+	}
+	return a < b
+}
+
+// exportComment heads the manifests written by Marshal.
+const exportComment = "Exported by uthoctl. Secrets, such as root passwords, are not included."
+
+// Marshal returns m as YAML that Parse reads back, with the fields of each
+// resource in the order they are documented and lists as sequences.
+func (m *Manifest) Marshal() ([]byte, error) {
+	resources := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, r := range m.Resources {
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		add := func(key, value string) {
+			node.Content = append(node.Content, scalar(key), valueNode(value))
+		}
+		add("kind", r.Kind)
+		add("name", r.Name)
+		for _, field := range kinds[r.Kind].fields {
+			if value, ok := r.Fields[field]; ok {
+				add(field, value)
+			}
+		}
+		resources.Content = append(resources.Content, node)
+	}
+
+	doc := &yaml.Node{Kind: yaml.MappingNode, HeadComment: exportComment}
+	doc.Content = []*yaml.Node{scalar("resources"), resources}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), enc.Close()
+}
+
+// valueNode returns the node of a field value, a sequence for lists.
+func valueNode(value string) *yaml.Node {
+	if !strings.Contains(value, ",") {
+		return scalar(value)
+	}
+	list := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+	for _, item := range strings.Split(value, ",") {
+		list.Content = append(list.Content, scalar(item))
+	}
+	return list
+}
+
+// scalar returns a string node, unquoted when it is a number since Parse
+// reads every value as a string.
+func scalar(value string) *yaml.Node {
+	tag := "!!str"
+	if _, err := strconv.ParseUint(value, 10, 64); err == nil {
+		tag = "!!int"
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+}
@@ -0,0 +1,643 @@
+package manifest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/uthoplatforms/utho-cli/helper"
+	"github.com/uthoplatforms/utho-go/utho"
+)
+
+// Live is a resource of the account, with the fields a manifest would give
+// it and the outputs of its kind.
+type Live struct {
+	ID string
+	// Name is empty for the kinds that have no name in the API, such as
+	// firewall rules.
+	Name   string
+	Fields map[string]string
+}
+
+// kind describes the resources of a kind: their fields, the resources they
+// reference, and how to list and create them.
+type kind struct {
+	// parent is the reference field naming the resource those of this kind
+	// belong to, eg: the firewall of a firewall rule.
+	parent string
+	// fields are the fields besides kind and name, in the order they are
+	// documented, and required those that must be set.
+	fields   []string
+	required []string
+	// secrets are the fields whose values are never shown.
+	secrets []string
+	// refs are the reference fields, with the kind of resource they name.
+	// A list field names several.
+	refs map[string]string
+	// key are the fields identifying an existing resource, which is matched
+	// by name when there are none.
+	key []string
+	// outputs are the fields of Live, besides the ID, that other resources
+	// can embed with ${kind.name.output}.
+	outputs []string
+	// list returns the resources of the account of this kind, those of the
+	// parent resource with ID parent when the kind has one.
+	list func(client utho.Client, parent string) ([]Live, error)
+	// create creates a resource with name and fields, references replaced
+	// by IDs, and returns its ID.
+	create func(client utho.Client, name string, fields map[string]string) (string, error)
+}
+
+func (k *kind) has(field string) bool {
+	return contains(k.fields, field)
+}
+
+func (k *kind) hasOutput(output string) bool {
+	return contains(k.outputs, output)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// kindOrder are the kinds of resources in an order where the kinds a
+// resource can reference come before its own.
+var kindOrder = []string{
+	"vpc", "firewall", "firewallrule", "instance", "kubernetes",
+	"targetgroup", "target", "loadbalancer", "frontend", "acl", "backend", "route",
+	"autoscaling", "policy", "schedule", "domain", "record",
+}
+
+// Kinds returns the kinds of resources manifests can describe, each after
+// those its resources can reference.
+func Kinds() []string {
+	return append([]string(nil), kindOrder...)
+}
+
+// resolvers are the helper kinds of the resources that references can name
+// without them being in the manifest.
+var resolvers = map[string]string{
+	"firewall":     helper.KindFirewall,
+	"instance":     helper.KindInstance,
+	"kubernetes":   helper.KindCluster,
+	"autoscaling":  helper.KindAutoscaling,
+	"loadbalancer": helper.KindLoadbalancer,
+	"targetgroup":  helper.KindTargetGroup,
+	"vpc":          helper.KindVPC,
+}
+
+// resolvable reports whether a reference to a resource of kind named name,
+// which is not in the manifest, can be resolved against the account.
+// Domains are named by themselves.
+func resolvable(kind, name string) bool {
+	_, ok := resolvers[kind]
+	return ok || kind == "domain" || helper.IsID(name)
+}
+
+var kinds = map[string]*kind{
+	"vpc": {
+		fields:   []string{"dcslug", "planid", "network", "size"},
+		required: []string{"dcslug", "planid", "network", "size"},
+		list: func(client utho.Client, _ string) ([]Live, error) {
+			vpcs, err := client.Vpc().List()
+			return lives(vpcs, err, func(v utho.Vpc) Live {
+				return Live{v.ID, v.Name, map[string]string{"dcslug": v.Dcslug, "network": v.Network, "size": v.Size}}
+			})
+		},
+		create: func(client utho.Client, name string, f map[string]string) (string, error) {
+			vpc, err := client.Vpc().Create(utho.CreateVpcParams{
+				Name:    name,
+				Dcslug:  f["dcslug"],
+				Planid:  f["planid"],
+				Network: f["network"],
+				Size:    f["size"],
+			})
+			return createdID(vpc, err)
+		},
+	},
+	"firewall": {
+		list: func(client utho.Client, _ string) ([]Live, error) {
+			firewalls, err := client.Firewall().List()
+			return lives(firewalls, err, func(fw utho.Firewall) Live { return Live{fw.ID, fw.Name, map[string]string{}} })
+		},
+		create: func(client utho.Client, name string, _ map[string]string) (string, error) {
+			firewall, err := client.Firewall().Create(utho.CreateFirewallParams{Name: name})
+			if err != nil {
+				return "", err
+			}
+			return firewall.ID, nil
+		},
+	},
+	"firewallrule": {
+		parent:   "firewall",
+		fields:   []string{"firewall", "type", "service", "protocol", "port", "addresses"},
+		required: []string{"firewall", "type", "protocol", "port", "addresses"},
+		refs:     map[string]string{"firewall": "firewall"},
+		key:      []string{"type", "protocol", "port", "addresses"},
+		list: func(client utho.Client, firewall string) ([]Live, error) {
+			rules, err := client.Firewall().ListFirewallRules(firewall)
+			return lives(rules, err, func(rule utho.FirewallRule) Live {
+				return Live{rule.ID, "", map[string]string{
+					"firewall":  firewall,
+					"type":      rule.Type,
+					"service":   rule.Service,
+					"protocol":  rule.Protocol,
+					"port":      rule.Port,
+					"addresses": rule.Addresses,
+				}}
+			})
+		},
+		create: func(client utho.Client, _ string, f map[string]string) (string, error) {
+			rule, err := client.Firewall().CreateFirewallRule(utho.CreateFirewallRuleParams{
+				FirewallId: f["firewall"],
+				Type:       f["type"],
+				Service:    f["service"],
+				Protocol:   f["protocol"],
+				Port:       f["port"],
+				Addresses:  f["addresses"],
+			})
+			return createdID(rule, err)
+		},
+	},
+	"instance": {
+		fields: []string{"dcslug", "image", "planid", "auth", "root_password", "firewall", "enablebackup",
+			"support", "management", "billingcycle", "backupid", "snapshotid", "sshkeys"},
+		required: []string{"dcslug", "image", "planid"},
+		secrets:  []string{"root_password"},
+		refs:     map[string]string{"firewall": "firewall"},
+		outputs:  []string{"ip"},
+		list: func(client utho.Client, _ string) ([]Live, error) {
+			instances, err := client.CloudInstances().List()
+			return lives(instances, err, func(i utho.CloudInstance) Live {
+				fields := map[string]string{
+					"dcslug":       i.Dclocation.Dc,
+					"image":        i.Image.Image,
+					"billingcycle": i.Billingcycle,
+					"ip":           i.IP,
+				}
+				if len(i.Firewalls) > 0 {
+					fields["firewall"] = i.Firewalls[0].ID
+				}
+				return Live{i.ID, i.Hostname, fields}
+			})
+		},
+		create: func(client utho.Client, name string, f map[string]string) (string, error) {
+			instance, err := client.CloudInstances().Create(utho.CreateCloudInstanceParams{
+				Dcslug:       f["dcslug"],
+				Image:        f["image"],
+				Planid:       f["planid"],
+				Auth:         f["auth"],
+				RootPassword: f["root_password"],
+				Firewall:     f["firewall"],
+				Enablebackup: f["enablebackup"],
+				Support:      f["support"],
+				Management:   f["management"],
+				Billingcycle: f["billingcycle"],
+				Backupid:     f["backupid"],
+				Snapshotid:   f["snapshotid"],
+				Sshkeys:      f["sshkeys"],
+				Cloud:        []utho.CloudHostname{{Hostname: name}},
+			})
+			if err != nil {
+				return "", err
+			}
+			return instance.ID, nil
+		},
+	},
+	"kubernetes": {
+		fields:   []string{"dcslug", "cluster_version", "auth", "vpc", "security_groups"},
+		required: []string{"dcslug"},
+		refs:     map[string]string{"vpc": "vpc", "security_groups": "firewall"},
+		outputs:  []string{"ip"},
+		list: func(client utho.Client, _ string) ([]Live, error) {
+			clusters, err := client.Kubernetes().List()
+			return lives(clusters, err, func(k utho.K8s) Live {
+				return Live{k.ID, k.Hostname, map[string]string{
+					"dcslug":          k.Dcslug,
+					"security_groups": joinIDs(k.SecurityGroups, func(sg utho.K8sSecurityGroups) string { return sg.ID }),
+					"ip":              k.IP,
+				}}
+			})
+		},
+		create: func(client utho.Client, name string, f map[string]string) (string, error) {
+			cluster, err := client.Kubernetes().Create(utho.CreateKubernetesParams{
+				Dcslug:         f["dcslug"],
+				ClusterLabel:   name,
+				ClusterVersion: f["cluster_version"],
+				Nodepools:      []utho.CreateNodepoolsParams{},
+				Auth:           f["auth"],
+				Vpc:            f["vpc"],
+				SecurityGroups: f["security_groups"],
+			})
+			return createdID(cluster, err)
+		},
+	},
+	"targetgroup": {
+		fields: []string{"protocol", "port", "health_check_path", "health_check_protocol", "health_check_interval",
+			"health_check_timeout", "healthy_threshold", "unhealthy_threshold"},
+		required: []string{"protocol", "port"},
+		list: func(client utho.Client, _ string) ([]Live, error) {
+			targetgroups, err := client.TargetGroup().List()
+			return lives(targetgroups, err, func(tg utho.TargetGroup) Live {
+				return Live{tg.ID, tg.Name, map[string]string{
+					"protocol":              tg.Protocol,
+					"port":                  tg.Port,
+					"health_check_path":     tg.HealthCheckPath,
+					"health_check_protocol": tg.HealthCheckProtocol,
+					"health_check_interval": tg.HealthCheckInterval,
+					"health_check_timeout":  tg.HealthCheckTimeout,
+					"healthy_threshold":     tg.HealthyThreshold,
+					"unhealthy_threshold":   tg.UnhealthyThreshold,
+				}}
+			})
+		},
+		create: func(client utho.Client, name string, f map[string]string) (string, error) {
+			targetgroup, err := client.TargetGroup().Create(utho.CreateTargetGroupParams{
+				Name:                name,
+				Protocol:            f["protocol"],
+				Port:                f["port"],
+				HealthCheckPath:     f["health_check_path"],
+				HealthCheckProtocol: f["health_check_protocol"],
+				HealthCheckInterval: f["health_check_interval"],
+				HealthCheckTimeout:  f["health_check_timeout"],
+				HealthyThreshold:    f["healthy_threshold"],
+				UnhealthyThreshold:  f["unhealthy_threshold"],
+			})
+			return createdID(targetgroup, err)
+		},
+	},
+	"target": {
+		parent:   "targetgroup",
+		fields:   []string{"targetgroup", "instance", "ip", "backend_port", "backend_protocol"},
+		required: []string{"targetgroup", "ip", "backend_port", "backend_protocol"},
+		refs:     map[string]string{"targetgroup": "targetgroup", "instance": "instance"},
+		key:      []string{"ip", "backend_port"},
+		list: func(client utho.Client, targetgroup string) ([]Live, error) {
+			targets, err := client.TargetGroup().ListTargets(targetgroup)
+			return lives(targets, err, func(t utho.Target) Live {
+				return Live{t.ID, "", map[string]string{
+					"targetgroup":      targetgroup,
+					"instance":         t.Cloudid,
+					"ip":               t.IP,
+					"backend_port":     t.BackendPort,
+					"backend_protocol": t.BackendProtocol,
+				}}
+			})
+		},
+		create: func(client utho.Client, _ string, f map[string]string) (string, error) {
+			target, err := client.TargetGroup().CreateTarget(utho.CreateTargetGroupTargetParams{
+				TargetGroupId:   f["targetgroup"],
+				BackendProtocol: f["backend_protocol"],
+				BackendPort:     f["backend_port"],
+				IP:              f["ip"],
+				Cloudid:         f["instance"],
+			})
+			return createdID(target, err)
+		},
+	},
+	"loadbalancer": {
+		fields:   []string{"dcslug", "type"},
+		required: []string{"dcslug"},
+		outputs:  []string{"ip"},
+		list: func(client utho.Client, _ string) ([]Live, error) {
+			loadbalancers, err := client.Loadbalancers().List()
+			return lives(loadbalancers, err, func(lb utho.Loadbalancer) Live {
+				return Live{lb.ID, lb.Name, map[string]string{"type": lb.Type, "ip": lb.IP}}
+			})
+		},
+		create: func(client utho.Client, name string, f map[string]string) (string, error) {
+			loadbalancer, err := client.Loadbalancers().Create(utho.CreateLoadbalancerParams{
+				Name:   name,
+				Dcslug: f["dcslug"],
+				Type:   f["type"],
+			})
+			if err != nil {
+				return "", err
+			}
+			return loadbalancer.ID, nil
+		},
+	},
+	"frontend": {
+		parent:   "loadbalancer",
+		fields:   []string{"loadbalancer", "proto", "port", "certificate_id", "algorithm", "redirecthttps", "cookie"},
+		required: []string{"loadbalancer", "proto", "port"},
+		refs:     map[string]string{"loadbalancer": "loadbalancer"},
+		list: func(client utho.Client, loadbalancer string) ([]Live, error) {
+			lb, err := client.Loadbalancers().Read(loadbalancer)
+			if err != nil {
+				return nil, err
+			}
+			return lives(lb.Frontends, nil, func(f utho.Frontends) Live {
+				return Live{f.ID, f.Name, map[string]string{
+					"loadbalancer":   loadbalancer,
+					"proto":          f.Proto,
+					"port":           f.Port,
+					"certificate_id": f.CertificateID,
+					"algorithm":      f.Algorithm,
+					"redirecthttps":  f.Redirecthttps,
+					"cookie":         f.Cookie,
+				}}
+			})
+		},
+		create: func(client utho.Client, name string, f map[string]string) (string, error) {
+			frontend, err := client.Loadbalancers().CreateFrontend(utho.CreateLoadbalancerFrontendParams{
+				LoadbalancerId: f["loadbalancer"],
+				Name:           name,
+				Proto:          f["proto"],
+				Port:           f["port"],
+				CertificateID:  f["certificate_id"],
+				Algorithm:      f["algorithm"],
+				Redirecthttps:  f["redirecthttps"],
+				Cookie:         f["cookie"],
+			})
+			return createdID(frontend, err)
+		},
+	},
+	"acl": {
+		parent:   "loadbalancer",
+		fields:   []string{"loadbalancer", "frontend", "condition_type", "value"},
+		required: []string{"loadbalancer", "frontend", "condition_type"},
+		refs:     map[string]string{"loadbalancer": "loadbalancer", "frontend": "frontend"},
+		list: func(client utho.Client, loadbalancer string) ([]Live, error) {
+			lb, err := client.Loadbalancers().Read(loadbalancer)
+			if err != nil {
+				return nil, err
+			}
+			return lives(lb.Acls, nil, func(acl utho.ACLs) Live {
+				return Live{acl.ID, acl.Name, map[string]string{
+					"loadbalancer":   loadbalancer,
+					"condition_type": acl.ACLCondition,
+					"value":          acl.Value,
+				}}
+			})
+		},
+		create: func(client utho.Client, name string, f map[string]string) (string, error) {
+			acl, err := client.Loadbalancers().CreateACL(utho.CreateLoadbalancerACLParams{
+				LoadbalancerId: f["loadbalancer"],
+				Name:           name,
+				ConditionType:  f["condition_type"],
+				FrontendID:     f["frontend"],
+				Value:          f["value"],
+			})
+			return createdID(acl, err)
+		},
+	},
+	"backend": {
+		parent:   "loadbalancer",
+		fields:   []string{"loadbalancer", "frontend", "instance", "port"},
+		required: []string{"loadbalancer", "frontend", "instance"},
+		refs:     map[string]string{"loadbalancer": "loadbalancer", "frontend": "frontend", "instance": "instance"},
+		key:      []string{"instance"},
+		list: func(client utho.Client, loadbalancer string) ([]Live, error) {
+			lb, err := client.Loadbalancers().Read(loadbalancer)
+			if err != nil {
+				return nil, err
+			}
+			return lives(lb.Backends, nil, func(b utho.Backends) Live {
+				return Live{b.ID, "", map[string]string{"loadbalancer": loadbalancer, "instance": b.Cloudid}}
+			})
+		},
+		create: func(client utho.Client, _ string, f map[string]string) (string, error) {
+			backend, err := client.Loadbalancers().CreateBackend(utho.CreateLoadbalancerBackendParams{
+				LoadbalancerId: f["loadbalancer"],
+				FrontendID:     f["frontend"],
+				BackendPort:    f["port"],
+				Cloudid:        f["instance"],
+			})
+			return createdID(backend, err)
+		},
+	},
+	"route": {
+		parent:   "loadbalancer",
+		fields:   []string{"loadbalancer", "frontend", "acl", "route_condition", "target_groups"},
+		required: []string{"loadbalancer", "frontend", "acl"},
+		refs: map[string]string{"loadbalancer": "loadbalancer", "frontend": "frontend", "acl": "acl",
+			"target_groups": "targetgroup"},
+		key: []string{"acl"},
+		list: func(client utho.Client, loadbalancer string) ([]Live, error) {
+			lb, err := client.Loadbalancers().Read(loadbalancer)
+			if err != nil {
+				return nil, err
+			}
+			return lives(lb.Routes, nil, func(r utho.Routes) Live {
+				return Live{r.ID, "", map[string]string{
+					"loadbalancer":    loadbalancer,
+					"acl":             r.ACLID,
+					"route_condition": r.RoutingCondition,
+				}}
+			})
+		},
+		create: func(client utho.Client, _ string, f map[string]string) (string, error) {
+			route, err := client.Loadbalancers().CreateRoute(utho.CreateLoadbalancerRouteParams{
+				LoadbalancerId: f["loadbalancer"],
+				FrontendID:     f["frontend"],
+				ACLID:          f["acl"],
+				RouteCondition: f["route_condition"],
+				TargetGroups:   f["target_groups"],
+			})
+			return createdID(route, err)
+		},
+	},
+	"autoscaling": {
+		fields: []string{"dcslug", "planid", "planname", "minsize", "maxsize", "desiredsize", "os_disk_size",
+			"instance_templateid", "public_ip_enabled", "vpc", "load_balancers", "security_groups", "stackid",
+			"stackimage", "target_groups"},
+		required: []string{"dcslug", "planid", "minsize", "maxsize", "desiredsize"},
+		refs: map[string]string{"vpc": "vpc", "load_balancers": "loadbalancer", "security_groups": "firewall",
+			"target_groups": "targetgroup"},
+		list: func(client utho.Client, _ string) ([]Live, error) {
+			groups, err := client.AutoScaling().List()
+			return lives(groups, err, func(g utho.Groups) Live {
+				return Live{g.ID, g.Name, map[string]string{
+					"dcslug":              g.Dcslug,
+					"planid":              g.Planid,
+					"planname":            g.Planname,
+					"minsize":             g.Minsize,
+					"maxsize":             g.Maxsize,
+					"desiredsize":         g.Desiredsize,
+					"instance_templateid": g.InstanceTemplateid,
+					"load_balancers":      joinIDs(g.Loadbalancers, func(lb utho.AutoScalingLoadbalancers) string { return lb.ID }),
+					"security_groups":     joinIDs(g.SecurityGroups, func(sg utho.SecurityGroup) string { return sg.ID }),
+					"target_groups":       joinIDs(g.TargetGroups, func(tg utho.AutoScalingTargetGroup) string { return tg.ID }),
+				}}
+			})
+		},
+		create: func(client utho.Client, name string, f map[string]string) (string, error) {
+			params := utho.CreateAutoScalingParams{
+				Name:               name,
+				Dcslug:             f["dcslug"],
+				Minsize:            f["minsize"],
+				Maxsize:            f["maxsize"],
+				Desiredsize:        f["desiredsize"],
+				Planid:             f["planid"],
+				Planname:           f["planname"],
+				InstanceTemplateid: f["instance_templateid"],
+				Vpc:                f["vpc"],
+				LoadBalancers:      f["load_balancers"],
+				SecurityGroups:     f["security_groups"],
+				Policies:           []utho.CreatePoliciesParams{},
+				Schedules:          []utho.CreateSchedulesParams{},
+				Stackid:            f["stackid"],
+				Stackimage:         f["stackimage"],
+				TargetGroups:       f["target_groups"],
+			}
+			var err error
+			if size := f["os_disk_size"]; size != "" {
+				if params.OsDiskSize, err = strconv.Atoi(size); err != nil {
+					return "", fmt.Errorf("os_disk_size: %w", err)
+				}
+			}
+			if enabled := f["public_ip_enabled"]; enabled != "" {
+				if params.PublicIPEnabled, err = helper.StringToBool(enabled); err != nil {
+					return "", fmt.Errorf("public_ip_enabled: %w", err)
+				}
+			}
+			group, err := client.AutoScaling().Create(params)
+			if err != nil {
+				return "", err
+			}
+			return strconv.Itoa(group.ID), nil
+		},
+	},
+	"policy": {
+		parent:   "autoscaling",
+		fields:   []string{"autoscaling", "type", "compare", "value", "adjust", "period", "cooldown", "product"},
+		required: []string{"autoscaling"},
+		refs:     map[string]string{"autoscaling": "autoscaling"},
+		list: func(client utho.Client, group string) ([]Live, error) {
+			policies, err := client.AutoScaling().ListPolicies(group)
+			return lives(policies, err, func(p utho.Policy) Live {
+				return Live{p.ID, p.Name, map[string]string{
+					"autoscaling": group,
+					"type":        p.Type,
+					"compare":     p.Compare,
+					"value":       p.Value,
+					"adjust":      p.Adjust,
+					"period":      p.Period,
+					"cooldown":    p.Cooldown,
+					"product":     p.Product,
+				}}
+			})
+		},
+		create: func(client utho.Client, name string, f map[string]string) (string, error) {
+			policy, err := client.AutoScaling().CreatePolicy(utho.CreateAutoScalingPolicyParams{
+				Name:      name,
+				Type:      f["type"],
+				Compare:   f["compare"],
+				Value:     f["value"],
+				Adjust:    f["adjust"],
+				Period:    f["period"],
+				Cooldown:  f["cooldown"],
+				Product:   f["product"],
+				Productid: f["autoscaling"],
+			})
+			return createdID(policy, err)
+		},
+	},
+	"schedule": {
+		parent:   "autoscaling",
+		fields:   []string{"autoscaling", "desiredsize", "recurrence", "start_date"},
+		required: []string{"autoscaling", "desiredsize"},
+		refs:     map[string]string{"autoscaling": "autoscaling"},
+		list: func(client utho.Client, group string) ([]Live, error) {
+			schedules, err := client.AutoScaling().ListSchedules(group)
+			return lives(schedules, err, func(s utho.Schedule) Live {
+				return Live{s.ID, s.Name, map[string]string{
+					"autoscaling": group,
+					"desiredsize": s.Desiredsize,
+					"recurrence":  s.Recurrence,
+					"start_date":  s.StartDate,
+				}}
+			})
+		},
+		create: func(client utho.Client, name string, f map[string]string) (string, error) {
+			schedule, err := client.AutoScaling().CreateSchedule(utho.CreateAutoScalingScheduleParams{
+				AutoScalingId: f["autoscaling"],
+				Name:          name,
+				Desiredsize:   f["desiredsize"],
+				Recurrence:    f["recurrence"],
+				StartDate:     f["start_date"],
+			})
+			return createdID(schedule, err)
+		},
+	},
+	"domain": {
+		list: func(client utho.Client, _ string) ([]Live, error) {
+			domains, err := client.Domain().ListDomains()
+			return lives(domains, err, func(d utho.Domain) Live { return Live{d.Domain, d.Domain, map[string]string{}} })
+		},
+		create: func(client utho.Client, name string, _ map[string]string) (string, error) {
+			// Domains are their own ID.
+			if _, err := client.Domain().CreateDomain(utho.CreateDomainParams{Domain: name}); err != nil {
+				return "", err
+			}
+			return name, nil
+		},
+	},
+	"record": {
+		parent:   "domain",
+		fields:   []string{"domain", "type", "hostname", "value", "ttl", "porttype", "port", "priority", "wight"},
+		required: []string{"domain", "type", "hostname", "value"},
+		refs:     map[string]string{"domain": "domain"},
+		key:      []string{"type", "hostname"},
+		list: func(client utho.Client, domain string) ([]Live, error) {
+			records, err := client.Domain().ListDnsRecords(domain)
+			return lives(records, err, func(r utho.DnsRecord) Live {
+				return Live{r.ID, "", map[string]string{
+					"domain":   domain,
+					"type":     r.Type,
+					"hostname": r.Hostname,
+					"value":    r.Value,
+					"ttl":      r.TTL,
+					"priority": r.Priority,
+				}}
+			})
+		},
+		create: func(client utho.Client, _ string, f map[string]string) (string, error) {
+			record, err := client.Domain().CreateDnsRecord(utho.CreateDnsRecordParams{
+				Domain:   f["domain"],
+				Type:     f["type"],
+				Hostname: f["hostname"],
+				Value:    f["value"],
+				TTL:      f["ttl"],
+				Porttype: f["porttype"],
+				Port:     f["port"],
+				Priority: f["priority"],
+				Wight:    f["wight"],
+			})
+			return createdID(record, err)
+		},
+	},
+}
+
+func lives[T any](items []T, err error, f func(T) Live) ([]Live, error) {
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]Live, len(items))
+	for i, item := range items {
+		resources[i] = f(item)
+	}
+	return resources, nil
+}
+
+// joinIDs returns the IDs of items, as a list reference field holds them.
+func joinIDs[T any](items []T, id func(T) string) string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = id(item)
+	}
+	return strings.Join(ids, ",")
+}
+
+func createdID(resp *utho.CreateResponse, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
@@ -0,0 +1,298 @@
+// Package manifest reads the YAML manifests of uthoctl apply, which describe
+// a set of resources and how they reference each other, brings an account
+// in line with them, and exports the resources of an account as one.
+//
+// A manifest is a list of resources, each with a kind, a name unique among
+// the resources of its kind with the same parent and the fields of its
+// kind:
+//
+//	resources:
+//	  - kind: firewall
+//	    name: web
+//	  - kind: instance
+//	    name: web-1
+//	    dcslug: innoida
+//	    image: ubuntu-22.04-x86_64
+//	    planid: 10045
+//	    firewall: web
+//
+// Reference fields, such as firewall above, name another resource of the
+// manifest, an existing resource or give its ID. Any field can also embed
+// the ID or an output of another resource of the manifest as ${kind.name}
+// or ${kind.name.output}, eg: ${instance.web-1.ip}.
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest is a parsed and validated manifest.
+type Manifest struct {
+	// Resources are in the order they can be created in: every resource
+	// comes after the resources it references.
+	Resources []*Resource
+}
+
+// Resource is a resource described by a manifest.
+type Resource struct {
+	Kind string
+	Name string
+	// Fields are the fields of the resource as written in the manifest,
+	// lists joined with commas.
+	Fields map[string]string
+	// line is where the resource starts in the manifest.
+	line int
+	// deps are the resources of the manifest this one references.
+	deps []*Resource
+	// id is the ID of the resource of the account an exported resource
+	// describes.
+	id string
+}
+
+// String returns the kind and name of r, eg: "instance/web-1".
+func (r *Resource) String() string {
+	return r.Kind + "/" + r.Name
+}
+
+// document is the layout of a manifest file.
+type document struct {
+	Resources []yaml.Node `yaml:"resources"`
+}
+
+// interpolation matches the ${kind.name} and ${kind.name.output} references
+// in field values.
+var interpolation = regexp.MustCompile(`\$\{([a-z]+)\.([^}]+)\}`)
+
+// Parse reads the manifest in data, file being its name in errors. It checks
+// the kind, fields and references of every resource and orders them.
+func Parse(file string, data []byte) (*Manifest, error) {
+	var doc document
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	var resources []*Resource
+	seen := map[string]*Resource{}
+	for i := range doc.Resources {
+		r, err := parseResource(&doc.Resources[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", file, doc.Resources[i].Line, err)
+		}
+		// Names are unique among the resources of a kind with the same
+		// parent.
+		key := r.String() + "@" + r.Fields[kinds[r.Kind].parent]
+		if prev := seen[key]; prev != nil {
+			return nil, fmt.Errorf("%s:%d: %s is already defined at line %d", file, r.line, r, prev.line)
+		}
+		seen[key] = r
+		resources = append(resources, r)
+	}
+	if len(resources) == 0 {
+		return nil, fmt.Errorf("%s: no resources", file)
+	}
+
+	m := &Manifest{Resources: resources}
+	for _, r := range resources {
+		if err := m.link(r); err != nil {
+			return nil, fmt.Errorf("%s:%d: %s: %w", file, r.line, r, err)
+		}
+	}
+	if err := m.sort(); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return m, nil
+}
+
+func parseResource(node *yaml.Node) (*Resource, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, errors.New("a resource must be a mapping of its fields")
+	}
+
+	r := &Resource{Fields: map[string]string{}, line: node.Line}
+	var fields []string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		var s string
+		switch value.Kind {
+		case yaml.ScalarNode:
+			s = value.Value
+		case yaml.SequenceNode:
+			items := make([]string, len(value.Content))
+			for j, item := range value.Content {
+				if item.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("field %q must be a list of scalars", key)
+				}
+				items[j] = item.Value
+			}
+			s = strings.Join(items, ",")
+		default:
+			return nil, fmt.Errorf("field %q must be a scalar or a list", key)
+		}
+
+		switch key {
+		case "kind":
+			r.Kind = s
+		case "name":
+			r.Name = s
+		default:
+			r.Fields[key] = s
+			fields = append(fields, key)
+		}
+	}
+
+	if r.Kind == "" {
+		return nil, errors.New("missing kind")
+	}
+	k, ok := kinds[r.Kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown kind %q (available: %s)", r.Kind, strings.Join(Kinds(), ", "))
+	}
+	if r.Name == "" {
+		return nil, fmt.Errorf("%s without a name", r.Kind)
+	}
+	for _, field := range fields {
+		switch {
+		case len(k.fields) == 0:
+			return nil, fmt.Errorf("%s: unknown field %q, %ss only have a name", r, field, r.Kind)
+		case !k.has(field):
+			return nil, fmt.Errorf("%s: unknown field %q (available: %s)", r, field, strings.Join(k.fields, ", "))
+		}
+	}
+	return r, nil
+}
+
+// lookup returns the resource of the manifest with kind and name, or nil.
+// The resources of kinds that belong to a parent, such as frontends, only
+// need a name unique within it: among several, lookup returns the one with
+// the same parent as from.
+func (m *Manifest) lookup(kind, name string, from *Resource) (*Resource, error) {
+	var matches []*Resource
+	for _, r := range m.Resources {
+		if r.Kind == kind && r.Name == name {
+			matches = append(matches, r)
+		}
+	}
+	if len(matches) <= 1 {
+		if len(matches) == 0 {
+			return nil, nil
+		}
+		return matches[0], nil
+	}
+
+	parent := kinds[kind].parent
+	for _, r := range matches {
+		if from != nil && from.Fields[parent] != "" && r.Fields[parent] == from.Fields[parent] {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("%d %ss are named %q, it can only be referenced from a resource of the same %s", len(matches), kind, name, parent)
+}
+
+// link records the resources r references, checking that its
+// interpolations designate resources of the manifest.
+func (m *Manifest) link(r *Resource) error {
+	k := kinds[r.Kind]
+	for _, field := range k.fields {
+		value := r.Fields[field]
+		if kind, ok := k.refs[field]; ok && value != "" {
+			for _, name := range strings.Split(value, ",") {
+				dep, err := m.lookup(kind, name, r)
+				if err != nil {
+					return fmt.Errorf("%s: %w", field, err)
+				}
+				if dep != nil {
+					r.deps = append(r.deps, dep)
+				} else if !interpolation.MatchString(name) && !resolvable(kind, name) {
+					return fmt.Errorf("%s: no %s named %q in the manifest", field, kind, name)
+				}
+			}
+		}
+		for _, match := range interpolation.FindAllStringSubmatch(value, -1) {
+			dep, _, err := m.reference(match[1], match[2], r)
+			if err != nil {
+				return fmt.Errorf("%s: %w", field, err)
+			}
+			r.deps = append(r.deps, dep)
+		}
+	}
+	return nil
+}
+
+// reference returns the resource and output ("id" when not given) of the
+// ${kind.ref} interpolation in a field of from. Names may contain dots, as
+// domains do, so ref is first taken as a name, then as a name followed by an
+// output.
+func (m *Manifest) reference(kind, ref string, from *Resource) (*Resource, string, error) {
+	k, ok := kinds[kind]
+	if !ok {
+		return nil, "", fmt.Errorf("${%s.%s}: unknown kind %q", kind, ref, kind)
+	}
+	r, err := m.lookup(kind, ref, from)
+	if err != nil {
+		return nil, "", fmt.Errorf("${%s.%s}: %w", kind, ref, err)
+	}
+	if r != nil {
+		return r, "id", nil
+	}
+	if i := strings.LastIndex(ref, "."); i > 0 {
+		name, output := ref[:i], ref[i+1:]
+		r, err := m.lookup(kind, name, from)
+		if err != nil {
+			return nil, "", fmt.Errorf("${%s.%s}: %w", kind, ref, err)
+		}
+		if r != nil {
+			if output != "id" && !k.hasOutput(output) {
+				return nil, "", fmt.Errorf("${%s.%s}: %s has no output %q", kind, ref, kind, output)
+			}
+			return r, output, nil
+		}
+	}
+	return nil, "", fmt.Errorf("${%s.%s}: no %s named %q in the manifest", kind, ref, kind, ref)
+}
+
+// sort orders the resources so that each comes after those it references,
+// keeping the order of the manifest where references allow.
+func (m *Manifest) sort() error {
+	sorted := make([]*Resource, 0, len(m.Resources))
+	done := map[*Resource]bool{}
+	for len(sorted) < len(m.Resources) {
+		progress := false
+		for _, r := range m.Resources {
+			if done[r] || !allDone(r.deps, done) {
+				continue
+			}
+			done[r] = true
+			sorted = append(sorted, r)
+			progress = true
+			break
+		}
+		if !progress {
+			var cycle []string
+			for _, r := range m.Resources {
+				if !done[r] {
+					cycle = append(cycle, r.String())
+				}
+			}
+			return fmt.Errorf("reference cycle between %s", strings.Join(cycle, ", "))
+		}
+	}
+	m.Resources = sorted
+	return nil
+}
+
+func allDone(deps []*Resource, done map[*Resource]bool) bool {
+	for _, dep := range deps {
+		if !done[dep] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,168 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseOrder(t *testing.T) {
+	m, err := Parse("env.yaml", []byte(`
+resources:
+  - kind: record
+    name: www
+    domain: example.com
+    type: A
+    hostname: www
+    value: ${loadbalancer.web.ip}
+  - kind: route
+    name: web
+    loadbalancer: web
+    frontend: http
+    acl: web
+    target_groups: [web, 42]
+  - kind: acl
+    name: web
+    loadbalancer: web
+    frontend: http
+    condition_type: http_host
+  - kind: frontend
+    name: http
+    loadbalancer: web
+    proto: http
+    port: 80
+  - kind: targetgroup
+    name: web
+    protocol: HTTP
+    port: 80
+  - kind: loadbalancer
+    name: web
+    dcslug: innoida
+  - kind: domain
+    name: example.com
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var order []string
+	for _, r := range m.Resources {
+		order = append(order, r.String())
+	}
+	want := "targetgroup/web loadbalancer/web frontend/http acl/web route/web domain/example.com record/www"
+	if got := strings.Join(order, " "); got != want {
+		t.Errorf("Parse() order = %s, want %s", got, want)
+	}
+	if r, _ := m.lookup("route", "web", nil); r.Fields["target_groups"] != "web,42" {
+		t.Errorf("target_groups = %q, want the list joined with commas", r.Fields["target_groups"])
+	}
+}
+
+func TestReference(t *testing.T) {
+	m, err := Parse("env.yaml", []byte(`
+resources:
+  - {kind: domain, name: example.com}
+  - {kind: instance, name: web, dcslug: innoida, image: ubuntu, planid: 1}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		kind, ref    string
+		name, output string
+	}{
+		{"domain", "example.com", "example.com", "id"},
+		{"domain", "example.com.id", "example.com", "id"},
+		{"instance", "web", "web", "id"},
+		{"instance", "web.ip", "web", "ip"},
+	} {
+		r, output, err := m.reference(tt.kind, tt.ref, nil)
+		if err != nil || r.Name != tt.name || output != tt.output {
+			t.Errorf("reference(%q, %q) = %v, %q, %v, want %s/%s, %q", tt.kind, tt.ref, r, output, err, tt.kind, tt.name, tt.output)
+		}
+	}
+	if _, _, err := m.reference("domain", "example.com.ip", nil); err == nil {
+		t.Error(`reference("domain", "example.com.ip") succeeded, domains have no ip`)
+	}
+}
+
+func TestLookupByParent(t *testing.T) {
+	m, err := Parse("env.yaml", []byte(`
+resources:
+  - {kind: loadbalancer, name: a}
+  - {kind: loadbalancer, name: b}
+  - {kind: frontend, name: http, loadbalancer: a}
+  - {kind: frontend, name: http, loadbalancer: b}
+  - {kind: acl, name: web, loadbalancer: b, frontend: http}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	acl, _ := m.lookup("acl", "web", nil)
+	if len(acl.deps) != 2 || acl.deps[1].String() != "frontend/http" || acl.deps[1].Fields["loadbalancer"] != "b" {
+		t.Errorf("acl/web depends on %v, want the frontend of loadbalancer b", acl.deps)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tt := range []struct {
+		manifest, err string
+	}{
+		{"resources: []", "env.yaml: no resources"},
+		{"resource: []", "field resource not found"},
+		{"resources: [{name: web}]", "env.yaml:1: missing kind"},
+		{"resources: [{kind: server, name: web}]", `unknown kind "server"`},
+		{"resources: [{kind: firewall}]", "firewall without a name"},
+		{"resources: [{kind: firewall, name: web, rules: {a: b}}]", `field "rules" must be a scalar or a list`},
+		{"resources: [{kind: firewall, name: web}, {kind: firewall, name: web}]", "firewall/web is already defined at line 1"},
+		{"resources: [{kind: frontend, name: http, loadbalancer: a}, {kind: frontend, name: http, loadbalancer: a}]", "frontend/http is already defined at line 1"},
+		{"resources: [{kind: loadbalancer, name: a}, {kind: loadbalancer, name: b}, {kind: frontend, name: http, loadbalancer: a}, {kind: frontend, name: http, loadbalancer: b}, {kind: acl, name: web, loadbalancer: 1, frontend: http}]",
+			`frontend: 2 frontends are named "http"`},
+		{"resources: [{kind: acl, name: a, loadbalancer: 1, frontend: http, condition_type: http_host}]", `frontend: no frontend named "http" in the manifest`},
+		{"resources: [{kind: firewallrule, name: a, firewall: '${firewall.web}', type: incoming, protocol: tcp, port: 22, addresses: any}]",
+			`firewall: ${firewall.web}: no firewall named "web" in the manifest`},
+		{"resources: [{kind: domain, name: a}, {kind: domain, name: b}, {kind: record, name: r, domain: a, type: A, hostname: '${domain.b}', value: '${record.r}'}]",
+			"reference cycle between record/r"},
+	} {
+		_, err := Parse("env.yaml", []byte(tt.manifest))
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("Parse(%s) = %v, want an error containing %q", tt.manifest, err, tt.err)
+		}
+	}
+}
+
+func TestMarshalTerraform(t *testing.T) {
+	m, err := Parse("env.yaml", []byte(`
+resources:
+  - {kind: firewall, name: web}
+  - {kind: instance, name: 1st, dcslug: innoida, image: ubuntu-22.04-x86_64, firewall: web}
+  - {kind: domain, name: example.com}
+  - {kind: record, name: txt, domain: example.com, type: TXT, hostname: '@', value: '$${literal}'}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range m.Resources {
+		r.id = []string{"11", "12", "example.com", "13"}[i]
+	}
+
+	data, todo, err := m.MarshalTerraform()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"utho_cloud_instance._1st.planid"}; !reflect.DeepEqual(todo, want) {
+		t.Errorf("MarshalTerraform() placeholders = %q, want %q", todo, want)
+	}
+	for _, want := range []string{
+		`resource "utho_cloud_instance" "_1st" {`,
+		"  firewall = utho_firewall.web.id\n",
+		`  planid   = "TODO" # TODO: the API does not return it`,
+		`  value    = "$$${literal}"`,
+		"  to = utho_dns_record.example_com_txt\n  id = \"example.com/13\"\n",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("MarshalTerraform() has no %q:\n%s", want, data)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/uthoplatforms/utho-cli/helper"
+	"github.com/uthoplatforms/utho-go/utho"
+)
+
+// Actions of a Change.
+const (
+	// Create is planned for the resources missing from the account, and
+	// Created reported once Apply created them.
+	Create  = "create"
+	Created = "created"
+	// Unchanged is for the resources the account already has.
+	Unchanged = "unchanged"
+	// Drifted is reported by Diff for the resources whose fields differ
+	// from the manifest, and Extra for the resources of the account that
+	// belong to a resource of the manifest without being in it, such as
+	// firewall rules added out of band.
+	Drifted = "drifted"
+	Extra   = "extra"
+)
+
+// Unknown is the value of the fields that reference a resource which is yet
+// to be created.
+const Unknown = "(known after apply)"
+
+// redacted replaces the values of secret fields in changes.
+const redacted = "REDACTED"
+
+// Change is what applying a manifest does to one of its resources.
+type Change struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	// ID is the ID of the resource, when it exists.
+	ID string `json:"id,omitempty"`
+	// Fields are the fields of the resource with references replaced by
+	// IDs, or Unknown, and secrets redacted.
+	Fields map[string]string `json:"fields,omitempty"`
+	// Diffs are the fields of an existing resource that differ from the
+	// manifest.
+	Diffs []FieldDiff `json:"diffs,omitempty"`
+}
+
+// FieldDiff is a field whose value in the account differs from the
+// manifest.
+type FieldDiff struct {
+	Field    string `json:"field"`
+	Manifest string `json:"manifest"`
+	Live     string `json:"live"`
+}
+
+// planner matches the resources of a manifest with those of the account,
+// listing the resources of each kind once.
+type planner struct {
+	client utho.Client
+	m      *Manifest
+	// ids are the IDs of the resources of the manifest known to exist.
+	ids  map[*Resource]string
+	live map[string][]Live
+	// matched are the kinds and IDs of the resources of the account
+	// described by the manifest.
+	matched map[string]bool
+}
+
+func newPlanner(client utho.Client, m *Manifest) *planner {
+	return &planner{client: client, m: m, ids: map[*Resource]string{}, live: map[string][]Live{}, matched: map[string]bool{}}
+}
+
+// Plan returns the changes applying m would make, in the order Apply makes
+// them, without making any.
+func Plan(client utho.Client, m *Manifest) ([]*Change, error) {
+	p := newPlanner(client, m)
+	changes := make([]*Change, 0, len(m.Resources))
+	for _, r := range m.Resources {
+		c, _, err := p.change(r)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+// Diff compares m with the account: it returns the changes of Plan, with
+// the resources whose fields differ from the manifest as Drifted, followed
+// by the Extra resources of the account.
+func Diff(client utho.Client, m *Manifest) ([]*Change, error) {
+	p := newPlanner(client, m)
+	changes := make([]*Change, 0, len(m.Resources))
+	for _, r := range m.Resources {
+		c, _, err := p.change(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(c.Diffs) > 0 {
+			c.Action = Drifted
+		}
+		changes = append(changes, c)
+	}
+
+	for _, r := range m.Resources {
+		id, ok := p.ids[r]
+		if !ok {
+			continue
+		}
+		for _, name := range Kinds() {
+			k := kinds[name]
+			if k.parent == "" || k.refs[k.parent] != r.Kind {
+				continue
+			}
+			live, err := p.list(name, id)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", r, err)
+			}
+			for _, l := range live {
+				if !p.matched[name+"/"+l.ID] {
+					changes = append(changes, &Change{Kind: name, Name: l.Name, Action: Extra, ID: l.ID, Fields: l.Fields})
+				}
+			}
+		}
+	}
+	return changes, nil
+}
+
+// Pending reports whether changes has any other than Unchanged.
+func Pending(changes []*Change) bool {
+	for _, c := range changes {
+		if c.Action != Unchanged {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply creates the resources of m missing from the account, each after
+// those it references. Nothing is created unless the whole manifest plans,
+// and it returns the changes made so far when creating one fails.
+func Apply(client utho.Client, m *Manifest) ([]*Change, error) {
+	// Plan every resource first, so that one missing a required field or
+	// referencing an unknown resource fails before anything is created.
+	if _, err := Plan(client, m); err != nil {
+		return nil, err
+	}
+
+	p := newPlanner(client, m)
+	changes := make([]*Change, 0, len(m.Resources))
+	for _, r := range m.Resources {
+		c, fields, err := p.change(r)
+		if err != nil {
+			return changes, err
+		}
+		if c.Action == Create {
+			k := kinds[r.Kind]
+			id, err := k.create(client, r.Name, fields)
+			if err != nil {
+				return changes, fmt.Errorf("creating %s: %w", r, err)
+			}
+			c.Action, c.ID = Created, id
+			p.ids[r] = id
+			delete(p.live, liveKey(r.Kind, fields[k.parent]))
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+// change plans r, whose references have been planned already, and returns
+// its fields with references replaced by IDs.
+func (p *planner) change(r *Resource) (*Change, map[string]string, error) {
+	fields, err := p.resolve(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", r, err)
+	}
+	c := &Change{Kind: r.Kind, Name: r.Name, Action: Create, Fields: map[string]string{}}
+	k := kinds[r.Kind]
+	for field, value := range fields {
+		if contains(k.secrets, field) {
+			value = redacted
+		}
+		c.Fields[field] = value
+	}
+
+	// The resources of a parent that is yet to be created are too.
+	if parent := fields[k.parent]; parent != Unknown {
+		live, err := p.list(r.Kind, parent)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", r, err)
+		}
+		if l := k.match(r.Name, fields, live); l != nil {
+			c.Action, c.ID = Unchanged, l.ID
+			c.Diffs = k.diff(fields, l.Fields)
+			p.ids[r] = l.ID
+			p.matched[r.Kind+"/"+l.ID] = true
+			return c, fields, nil
+		}
+	}
+
+	// Fields only needed to create a resource may be left out of those
+	// that exist, as in exported manifests.
+	for _, field := range k.required {
+		if r.Fields[field] == "" {
+			return nil, nil, fmt.Errorf("%s: missing %s, which is required to create it", r, field)
+		}
+	}
+	return c, fields, nil
+}
+
+// diff returns the fields set in the manifest, but secrets, whose value in
+// the account is known and different.
+func (k *kind) diff(fields, live map[string]string) []FieldDiff {
+	var diffs []FieldDiff
+	for _, field := range k.fields {
+		value, ok := fields[field]
+		liveValue, liveOK := live[field]
+		if ok && liveOK && value != Unknown && value != liveValue && !contains(k.secrets, field) {
+			diffs = append(diffs, FieldDiff{Field: field, Manifest: value, Live: liveValue})
+		}
+	}
+	return diffs
+}
+
+// match returns the resource of live that the resource with name and fields
+// describes, or nil. Among several, it prefers one whose other fields match
+// too.
+func (k *kind) match(name string, fields map[string]string, live []Live) *Live {
+	var candidates []*Live
+	for i := range live {
+		l := &live[i]
+		if len(k.key) == 0 && l.Name == name || len(k.key) > 0 && equal(k.key, fields, l.Fields) {
+			candidates = append(candidates, l)
+		}
+	}
+	for _, l := range candidates {
+		if equal(k.fields, fields, l.Fields) {
+			return l
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[0]
+	}
+	return nil
+}
+
+// equal reports whether the fields set in both a and b have the same value.
+// Unknown values are never equal.
+func equal(fields []string, a, b map[string]string) bool {
+	for _, field := range fields {
+		va, oka := a[field]
+		vb, okb := b[field]
+		if oka && okb && (va != vb || va == Unknown) {
+			return false
+		}
+	}
+	return true
+}
+
+func liveKey(kind, parent string) string {
+	return kind + "/" + parent
+}
+
+// list returns the resources of kind of the account, those of parent for
+// the kinds that have one.
+func (p *planner) list(kind, parent string) ([]Live, error) {
+	key := liveKey(kind, parent)
+	if live, ok := p.live[key]; ok {
+		return live, nil
+	}
+	live, err := kinds[kind].list(p.client, parent)
+	if err != nil {
+		return nil, err
+	}
+	p.live[key] = live
+	return live, nil
+}
+
+// resolve returns the fields of r with interpolations and references
+// replaced by IDs, or by Unknown for the resources yet to be created.
+func (p *planner) resolve(r *Resource) (map[string]string, error) {
+	k := kinds[r.Kind]
+	fields := make(map[string]string, len(r.Fields))
+	for _, field := range k.fields {
+		value, ok := r.Fields[field]
+		if !ok {
+			continue
+		}
+		value, err := p.interpolate(value, r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field, err)
+		}
+		if kind, ok := k.refs[field]; ok && value != Unknown {
+			names := strings.Split(value, ",")
+			for i, name := range names {
+				if names[i], err = p.ref(kind, name, r); err != nil {
+					return nil, fmt.Errorf("%s: %w", field, err)
+				}
+				if names[i] == Unknown {
+					value = Unknown
+				}
+			}
+			if value != Unknown {
+				value = strings.Join(names, ",")
+			}
+		}
+		fields[field] = value
+	}
+	return fields, nil
+}
+
+// interpolate replaces the ${kind.name.output} references of value, a field
+// of from. It returns Unknown when one of them is yet to be created.
+func (p *planner) interpolate(value string, from *Resource) (string, error) {
+	var err error
+	unknown := false
+	value = interpolation.ReplaceAllStringFunc(value, func(match string) string {
+		sub := interpolation.FindStringSubmatch(match)
+		dep, output, e := p.m.reference(sub[1], sub[2], from)
+		if e != nil {
+			err = e
+			return match
+		}
+		id, ok := p.ids[dep]
+		if !ok {
+			unknown = true
+			return match
+		}
+		if output == "id" {
+			return id
+		}
+		v, e := p.output(dep, id, output)
+		if e != nil {
+			err = e
+		}
+		return v
+	})
+	if unknown {
+		return Unknown, err
+	}
+	return value, err
+}
+
+// output returns the output of the resource of the manifest r, whose ID is
+// id.
+func (p *planner) output(r *Resource, id, output string) (string, error) {
+	live, err := p.list(r.Kind, "")
+	if err != nil {
+		return "", err
+	}
+	for _, l := range live {
+		if l.ID == id {
+			return l.Fields[output], nil
+		}
+	}
+	return "", fmt.Errorf("%s has no %s yet", r, output)
+}
+
+// ref returns the ID of the resource of kind named name in a field of from:
+// a resource of the manifest, or of the account.
+func (p *planner) ref(kind, name string, from *Resource) (string, error) {
+	r, err := p.m.lookup(kind, name, from)
+	if err != nil {
+		return "", err
+	}
+	if r != nil {
+		if id, ok := p.ids[r]; ok {
+			return id, nil
+		}
+		return Unknown, nil
+	}
+	if resolver, ok := resolvers[kind]; ok {
+		return helper.Resolve(p.client, resolver, name)
+	}
+	return name, nil
+}
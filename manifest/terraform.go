@@ -0,0 +1,148 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// terraformType is the resource of the Utho Terraform provider for a kind,
+// with the attribute holding the name of the resource, if any. The other
+// attributes are named after the fields of the kind.
+type terraformType struct {
+	resource string
+	name     string
+}
+
+var terraformTypes = map[string]terraformType{
+	"vpc":          {"utho_vpc", "name"},
+	"firewall":     {"utho_firewall", "name"},
+	"instance":     {"utho_cloud_instance", "name"},
+	"loadbalancer": {"utho_loadbalancer", "name"},
+	"domain":       {"utho_domain", "domain"},
+	"record":       {"utho_dns_record", ""},
+}
+
+// TerraformKinds returns the kinds of resources MarshalTerraform writes, in
+// the order of Kinds.
+func TerraformKinds() []string {
+	var names []string
+	for _, name := range kindOrder {
+		if _, ok := terraformTypes[name]; ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// unsafeLabel matches what Terraform labels replace with an underscore.
+var unsafeLabel = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// terraformPlaceholder stands for the value of a required attribute the
+// API does not return, such as the plan of an instance.
+const terraformPlaceholder = "TODO"
+
+// MarshalTerraform returns the resources of m that have a Terraform type as
+// HCL, each followed by an import block bringing the resource of the
+// account it was exported from under Terraform. References between them
+// are Terraform references.
+//
+// Required attributes missing from a resource, because the API does not
+// return them, are written with a placeholder and a TODO comment, and
+// returned as resource.label.attribute so that callers can warn about them.
+func (m *Manifest) MarshalTerraform() ([]byte, []string, error) {
+	var todo []string
+	labels := map[*Resource]string{}
+	used := map[string]bool{}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n", exportComment)
+	for _, r := range m.Resources {
+		tf, ok := terraformTypes[r.Kind]
+		if !ok {
+			continue
+		}
+		k := kinds[r.Kind]
+
+		// Resources are named uniquely within their parent only.
+		label := r.Name
+		parent := m.parent(r)
+		if parent != nil {
+			label = labels[parent] + "_" + label
+		}
+		label = strings.Trim(unsafeLabel.ReplaceAllString(label, "_"), "_")
+		if label == "" || label[0] >= '0' && label[0] <= '9' {
+			label = "_" + label
+		}
+		for i, base := 2, label; used[tf.resource+"."+label]; i++ {
+			label = base + "_" + strconv.Itoa(i)
+		}
+		used[tf.resource+"."+label] = true
+		labels[r] = label
+
+		// attrs are the name, value and comment of each attribute.
+		var attrs [][3]string
+		if tf.name != "" {
+			attrs = append(attrs, [3]string{tf.name, hclString(r.Name)})
+		}
+		for _, field := range k.fields {
+			value, ok := r.Fields[field]
+			if !ok {
+				if contains(k.required, field) {
+					attrs = append(attrs, [3]string{field, hclString(terraformPlaceholder), "# TODO: the API does not return it, set the value the resource was created with"})
+					todo = append(todo, tf.resource+"."+label+"."+field)
+				}
+				continue
+			}
+			expr := hclString(value)
+			if ref, ok := k.refs[field]; ok {
+				if dep, err := m.lookup(ref, value, r); err == nil && dep != nil && labels[dep] != "" {
+					expr = terraformTypes[ref].resource + "." + labels[dep] + ".id"
+				}
+			}
+			attrs = append(attrs, [3]string{field, expr})
+		}
+		width := 0
+		for _, attr := range attrs {
+			width = max(width, len(attr[0]))
+		}
+
+		fmt.Fprintf(&buf, "\nresource %q %q {\n", tf.resource, label)
+		for _, attr := range attrs {
+			line := fmt.Sprintf("  %-*s = %s", width, attr[0], attr[1])
+			if attr[2] != "" {
+				line += " " + attr[2]
+			}
+			fmt.Fprintln(&buf, line)
+		}
+		fmt.Fprintf(&buf, "}\n\nimport {\n  to = %s.%s\n  id = %s\n}\n", tf.resource, label, hclString(terraformID(r, parent)))
+	}
+	return buf.Bytes(), todo, nil
+}
+
+// parent returns the resource of m that r belongs to, or nil.
+func (m *Manifest) parent(r *Resource) *Resource {
+	k := kinds[r.Kind]
+	if k.parent == "" {
+		return nil
+	}
+	parent, _ := m.lookup(k.refs[k.parent], r.Fields[k.parent], r)
+	return parent
+}
+
+// terraformID returns the ID Terraform imports r by: its ID, preceded by
+// that of its parent, if any, eg: example.com/1024 for a DNS record.
+func terraformID(r, parent *Resource) string {
+	if parent != nil {
+		return parent.id + "/" + r.id
+	}
+	return r.id
+}
+
+// hclString quotes s as an HCL string, escaping the template sequences.
+func hclString(s string) string {
+	s = strconv.Quote(s)
+	s = strings.ReplaceAll(s, "${", "$${")
+	return strings.ReplaceAll(s, "%{", "%%{")
+}
@@ -0,0 +1,141 @@
+package printer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Fields returns the dotted paths of every scalar field of the utho-go type
+// behind v, descending into nested structs (eg: Image.Image). Slices and
+// maps are left out as they do not fit in a table cell. When v is a slice,
+// the fields of its elements are returned.
+func Fields(v any) []string {
+	return scalarFields(elemType(reflect.TypeOf(v)), "")
+}
+
+// NestedFields returns the fields of Fields, and those of the structs in
+// slices, such as Cloud.Hostname, whose table cells list the value of each
+// element.
+func NestedFields(v any) []string {
+	t := elemType(reflect.TypeOf(v))
+	fields := Fields(v)
+	if t == nil || t.Kind() != reflect.Struct {
+		return fields
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if ft := derefType(f.Type); f.IsExported() && ft.Kind() == reflect.Slice {
+			fields = append(fields, scalarFields(derefType(ft.Elem()), f.Name+".")...)
+		}
+	}
+	return fields
+}
+
+func scalarFields(t reflect.Type, prefix string) []string {
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		ft := derefType(f.Type)
+		switch {
+		case ft.Kind() == reflect.Struct:
+			fields = append(fields, scalarFields(ft, prefix+f.Name+".")...)
+		case isScalar(ft.Kind()):
+			fields = append(fields, prefix+f.Name)
+		}
+	}
+	return fields
+}
+
+// resolveField turns a user supplied field path into the Go field path of
+// t. Each segment may be the Go field name, the API name from the json tag
+// or either of them in another case, so "id", "ID" and "cloudid" all
+// resolve to ID on a CloudInstance.
+func resolveField(t reflect.Type, path string) (string, error) {
+	path = strings.TrimPrefix(strings.TrimSpace(path), ".")
+	if path == "" {
+		return "", fmt.Errorf("empty field name")
+	}
+
+	var resolved []string
+	current := elemType(t)
+	for _, name := range strings.Split(path, ".") {
+		if current == nil || current.Kind() != reflect.Struct {
+			return "", fmt.Errorf("unknown field %q", path)
+		}
+		f, ok := structField(current, name)
+		if !ok {
+			return "", fmt.Errorf("unknown field %q (available: %s)", path, strings.Join(scalarFields(elemType(t), ""), ", "))
+		}
+		resolved = append(resolved, f.Name)
+		current = elemType(f.Type)
+	}
+	return strings.Join(resolved, "."), nil
+}
+
+// structField finds a field of t by its Go name or json name, falling back
+// to a case-insensitive match.
+func structField(t reflect.Type, name string) (reflect.StructField, bool) {
+	fallback := -1
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		jsonName := jsonFieldName(f)
+		if f.Name == name || jsonName == name {
+			return f, true
+		}
+		if fallback < 0 && (strings.EqualFold(f.Name, name) || strings.EqualFold(jsonName, name)) {
+			fallback = i
+		}
+	}
+	if fallback >= 0 {
+		return t.Field(fallback), true
+	}
+	return reflect.StructField{}, false
+}
+
+func jsonFieldName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return f.Name
+	}
+	return name
+}
+
+// elemType returns the struct type shown on each table row: the element type
+// of slices, without pointers.
+func elemType(t reflect.Type) reflect.Type {
+	t = derefType(t)
+	if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = derefType(t.Elem())
+	}
+	return t
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func isScalar(k reflect.Kind) bool {
+	switch k {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,496 @@
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// jsonPath implements the subset of the kubectl JSONPath template syntax
+// that is useful against utho-go objects: literal text, {.field.path},
+// {.list[n]}, {.list[*]}, {.list[a:b]}, {.list[?(@.field=="value")]},
+// {"literal"} and {range ...}...{end} blocks.
+//
+// Field names match either the Go field name (Image.Image) or the API name
+// from the json tag (image.image). The returned objects are also reachable
+// as {.items[*]}, a single object being treated as a one element list.
+type jsonPath struct {
+	nodes []pathNode
+}
+
+type pathNode struct {
+	text  string
+	steps []pathStep
+	isVar bool
+	// children is set for range blocks.
+	children []pathNode
+}
+
+type pathStep struct {
+	kind   stepKind
+	name   string
+	index  int
+	start  *int
+	end    *int
+	filter *pathFilter
+}
+
+type stepKind int
+
+const (
+	stepField stepKind = iota
+	stepIndex
+	stepSlice
+	stepWildcard
+	stepFilter
+)
+
+type pathFilter struct {
+	steps []pathStep
+	op    string
+	value string
+}
+
+func parseJSONPath(tmpl string) (*jsonPath, error) {
+	tokens, err := splitTemplate(tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, rest, err := parseNodes(tokens, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("jsonpath: unexpected {end}")
+	}
+	return &jsonPath{nodes: nodes}, nil
+}
+
+type templateToken struct {
+	text string
+	expr bool
+}
+
+// splitTemplate separates literal text from {expressions}, taking care of
+// braces inside quoted strings.
+func splitTemplate(tmpl string) ([]templateToken, error) {
+	var tokens []templateToken
+	for len(tmpl) > 0 {
+		open := strings.IndexByte(tmpl, '{')
+		if open < 0 {
+			tokens = append(tokens, templateToken{text: tmpl})
+			break
+		}
+		if open > 0 {
+			tokens = append(tokens, templateToken{text: tmpl[:open]})
+		}
+
+		var quote byte
+		closing := -1
+		for i := open + 1; i < len(tmpl) && closing < 0; i++ {
+			switch c := tmpl[i]; {
+			case quote != 0 && c == '\\':
+				i++
+			case quote != 0 && c == quote:
+				quote = 0
+			case quote == 0 && (c == '"' || c == '\''):
+				quote = c
+			case quote == 0 && c == '}':
+				closing = i
+			}
+		}
+		if closing < 0 {
+			return nil, fmt.Errorf("jsonpath: unclosed expression in %q", tmpl[open:])
+		}
+		tokens = append(tokens, templateToken{text: strings.TrimSpace(tmpl[open+1 : closing]), expr: true})
+		tmpl = tmpl[closing+1:]
+	}
+	return tokens, nil
+}
+
+func parseNodes(tokens []templateToken, inRange bool) ([]pathNode, []templateToken, error) {
+	var nodes []pathNode
+	for len(tokens) > 0 {
+		tok := tokens[0]
+		tokens = tokens[1:]
+
+		switch {
+		case !tok.expr:
+			nodes = append(nodes, pathNode{text: tok.text})
+		case tok.text == "end":
+			if !inRange {
+				return nil, nil, fmt.Errorf("jsonpath: {end} without {range}")
+			}
+			return nodes, append([]templateToken{tok}, tokens...), nil
+		case strings.HasPrefix(tok.text, "range "):
+			steps, err := parseSteps(strings.TrimSpace(strings.TrimPrefix(tok.text, "range ")))
+			if err != nil {
+				return nil, nil, err
+			}
+			children, rest, err := parseNodes(tokens, true)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(rest) == 0 {
+				return nil, nil, fmt.Errorf("jsonpath: {range} without {end}")
+			}
+			tokens = rest[1:]
+			nodes = append(nodes, pathNode{steps: steps, isVar: true, children: children})
+		case strings.HasPrefix(tok.text, `"`) || strings.HasPrefix(tok.text, "'"):
+			text, err := unquote(tok.text)
+			if err != nil {
+				return nil, nil, fmt.Errorf("jsonpath: invalid literal %s", tok.text)
+			}
+			nodes = append(nodes, pathNode{text: text})
+		default:
+			steps, err := parseSteps(tok.text)
+			if err != nil {
+				return nil, nil, err
+			}
+			nodes = append(nodes, pathNode{steps: steps, isVar: true})
+		}
+	}
+	return nodes, nil, nil
+}
+
+func unquote(s string) (string, error) {
+	if strings.HasPrefix(s, "'") {
+		s = `"` + strings.ReplaceAll(strings.Trim(s, "'"), `"`, `\"`) + `"`
+	}
+	return strconv.Unquote(s)
+}
+
+// parseSteps parses an expression such as .items[*].Image.Image.
+func parseSteps(expr string) ([]pathStep, error) {
+	orig := expr
+	expr = strings.TrimPrefix(expr, "$")
+	expr = strings.TrimPrefix(expr, "@")
+
+	var steps []pathStep
+	for len(expr) > 0 {
+		switch expr[0] {
+		case '.':
+			expr = expr[1:]
+			if strings.HasPrefix(expr, "*") {
+				steps = append(steps, pathStep{kind: stepWildcard})
+				expr = expr[1:]
+				continue
+			}
+			end := strings.IndexAny(expr, ".[")
+			if end < 0 {
+				end = len(expr)
+			}
+			if name := expr[:end]; name != "" {
+				steps = append(steps, pathStep{kind: stepField, name: name})
+			}
+			expr = expr[end:]
+		case '[':
+			end := matchingBracket(expr)
+			if end < 0 {
+				return nil, fmt.Errorf("jsonpath: unclosed [ in %q", orig)
+			}
+			step, err := parseBracket(expr[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath: %v in %q", err, orig)
+			}
+			steps = append(steps, step)
+			expr = expr[end+1:]
+		default:
+			return nil, fmt.Errorf("jsonpath: unexpected %q in %q", expr[0], orig)
+		}
+	}
+	return steps, nil
+}
+
+func matchingBracket(expr string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parseBracket(inner string) (pathStep, error) {
+	inner = strings.TrimSpace(inner)
+	switch {
+	case inner == "*":
+		return pathStep{kind: stepWildcard}, nil
+	case strings.HasPrefix(inner, "?(") && strings.HasSuffix(inner, ")"):
+		return parseFilter(inner[2 : len(inner)-1])
+	case strings.HasPrefix(inner, "'") || strings.HasPrefix(inner, `"`):
+		name, err := unquote(inner)
+		if err != nil {
+			return pathStep{}, fmt.Errorf("invalid field name %s", inner)
+		}
+		return pathStep{kind: stepField, name: name}, nil
+	case strings.Contains(inner, ":"):
+		parts := strings.SplitN(inner, ":", 2)
+		step := pathStep{kind: stepSlice}
+		for i, part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return pathStep{}, fmt.Errorf("invalid slice %q", inner)
+			}
+			if i == 0 {
+				step.start = &n
+			} else {
+				step.end = &n
+			}
+		}
+		return step, nil
+	default:
+		n, err := strconv.Atoi(inner)
+		if err != nil {
+			return pathStep{}, fmt.Errorf("invalid index %q", inner)
+		}
+		return pathStep{kind: stepIndex, index: n}, nil
+	}
+}
+
+func parseFilter(expr string) (pathStep, error) {
+	for _, op := range []string{"==", "!="} {
+		left, right, found := strings.Cut(expr, op)
+		if !found {
+			continue
+		}
+		steps, err := parseSteps(strings.TrimSpace(left))
+		if err != nil {
+			return pathStep{}, err
+		}
+		value := strings.TrimSpace(right)
+		if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'") {
+			if value, err = unquote(value); err != nil {
+				return pathStep{}, fmt.Errorf("invalid filter value %s", right)
+			}
+		}
+		return pathStep{kind: stepFilter, filter: &pathFilter{steps: steps, op: op, value: value}}, nil
+	}
+	return pathStep{}, fmt.Errorf("unsupported filter %q", expr)
+}
+
+func (j *jsonPath) execute(w io.Writer, data any) error {
+	return executeNodes(w, j.nodes, reflect.ValueOf(data), true)
+}
+
+// executeNodes writes nodes evaluated against current, which atRoot tells
+// is the object given to execute rather than an element of a range.
+func executeNodes(w io.Writer, nodes []pathNode, current reflect.Value, atRoot bool) error {
+	for _, node := range nodes {
+		if !node.isVar {
+			if _, err := io.WriteString(w, node.text); err != nil {
+				return err
+			}
+			continue
+		}
+
+		values, err := evaluate(node.steps, current, atRoot)
+		if err != nil {
+			return &ExprError{err}
+		}
+
+		if node.children != nil {
+			for _, value := range values {
+				if err := executeNodes(w, node.children, value, false); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		texts := make([]string, 0, len(values))
+		for _, value := range values {
+			text, err := format(value)
+			if err != nil {
+				return err
+			}
+			texts = append(texts, text)
+		}
+		if _, err := io.WriteString(w, strings.Join(texts, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evaluate returns the values steps select from current. At the root, a
+// missing items field stands for the root object itself, see itemsOf.
+func evaluate(steps []pathStep, current reflect.Value, atRoot bool) ([]reflect.Value, error) {
+	values := []reflect.Value{current}
+	for i, step := range steps {
+		var next []reflect.Value
+		for _, value := range values {
+			value = indirect(value)
+			if !value.IsValid() {
+				continue
+			}
+
+			switch step.kind {
+			case stepField:
+				found, ok := lookup(value, step.name)
+				if !ok && i == 0 && step.name == "items" && atRoot {
+					found, ok = itemsOf(value), true
+				}
+				if !ok {
+					return nil, fmt.Errorf("jsonpath: field %q not found", step.name)
+				}
+				next = append(next, found)
+			case stepIndex:
+				if !isList(value) {
+					return nil, fmt.Errorf("jsonpath: cannot index %s", value.Kind())
+				}
+				n := step.index
+				if n < 0 {
+					n += value.Len()
+				}
+				if n < 0 || n >= value.Len() {
+					return nil, fmt.Errorf("jsonpath: index %d out of range", step.index)
+				}
+				next = append(next, value.Index(n))
+			case stepSlice:
+				if !isList(value) {
+					return nil, fmt.Errorf("jsonpath: cannot slice %s", value.Kind())
+				}
+				start, end := bounds(step, value.Len())
+				for n := start; n < end; n++ {
+					next = append(next, value.Index(n))
+				}
+			case stepWildcard:
+				next = append(next, children(value)...)
+			case stepFilter:
+				if !isList(value) {
+					return nil, fmt.Errorf("jsonpath: cannot filter %s", value.Kind())
+				}
+				for n := 0; n < value.Len(); n++ {
+					if matches(step.filter, value.Index(n)) {
+						next = append(next, value.Index(n))
+					}
+				}
+			}
+		}
+		values = next
+	}
+	return values, nil
+}
+
+func bounds(step pathStep, length int) (int, int) {
+	start, end := 0, length
+	if step.start != nil {
+		start = *step.start
+		if start < 0 {
+			start += length
+		}
+	}
+	if step.end != nil {
+		end = *step.end
+		if end < 0 {
+			end += length
+		}
+	}
+	start = max(0, min(start, length))
+	end = max(start, min(end, length))
+	return start, end
+}
+
+func matches(filter *pathFilter, value reflect.Value) bool {
+	found, err := evaluate(filter.steps, value, false)
+	if err != nil || len(found) == 0 {
+		return filter.op == "!="
+	}
+	text, err := format(found[0])
+	if err != nil {
+		return false
+	}
+	return (text == filter.value) == (filter.op == "==")
+}
+
+// itemsOf exposes the root object as a list, so {.items[*]} works the same
+// against get and list commands.
+func itemsOf(v reflect.Value) reflect.Value {
+	if isList(v) {
+		return v
+	}
+	list := reflect.MakeSlice(reflect.SliceOf(v.Type()), 1, 1)
+	list.Index(0).Set(v)
+	return list
+}
+
+func isList(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice || v.Kind() == reflect.Array
+}
+
+func children(v reflect.Value) []reflect.Value {
+	var out []reflect.Value
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			out = append(out, v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				out = append(out, v.Field(i))
+			}
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			out = append(out, v.MapIndex(key))
+		}
+	}
+	return out
+}
+
+// lookup finds a struct field by its Go or json name (see structField), or a
+// map entry by key.
+func lookup(v reflect.Value, name string) (reflect.Value, bool) {
+	switch v.Kind() {
+	case reflect.Struct:
+		if f, ok := structField(v.Type(), name); ok {
+			return v.FieldByIndex(f.Index), true
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.String {
+			if found := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key())); found.IsValid() {
+				return found, true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// format renders scalars as plain text and anything else as JSON.
+func format(v reflect.Value) (string, error) {
+	v = indirect(v)
+	if !v.IsValid() {
+		return "", nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		data, err := json.Marshal(v.Interface())
+		return string(data), err
+	default:
+		return fmt.Sprint(v.Interface()), nil
+	}
+}
@@ -0,0 +1,133 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+)
+
+type testImage struct {
+	Image        string `json:"image"`
+	Distribution string `json:"distribution"`
+}
+
+type testInstance struct {
+	ID       string            `json:"cloudid"`
+	Hostname string            `json:"hostname"`
+	Status   string            `json:"status"`
+	Image    testImage         `json:"image"`
+	IPs      []string          `json:"ips"`
+	Tags     map[string]string `json:"tags"`
+}
+
+type testList struct {
+	Items []string `json:"items"`
+}
+
+var testInstances = []testInstance{
+	{ID: "1", Hostname: "web-1", Status: "Active", Image: testImage{"ubuntu-22.04-x86_64", "ubuntu"}, IPs: []string{"10.0.0.1", "10.0.0.2"}, Tags: map[string]string{"env": "prod"}},
+	{ID: "2", Hostname: "web-2", Status: "Stopped", Image: testImage{"debian-12-x86_64", "debian"}},
+	{ID: "3", Hostname: "db-1", Status: "Active", Image: testImage{"rocky-9-x86_64", "rocky"}},
+}
+
+func printJSONPath(tmpl string, v any) (string, error) {
+	var buf bytes.Buffer
+	p, err := New("jsonpath="+tmpl, &buf)
+	if err != nil {
+		return "", err
+	}
+	err = p.Print(v)
+	return buf.String(), err
+}
+
+func TestJSONPath(t *testing.T) {
+	tests := []struct {
+		name string
+		tmpl string
+		data any
+		want string
+	}{
+		{"field", "{.items[0].hostname}", testInstances, "web-1"},
+		{"go field name", "{.items[0].Hostname}", testInstances, "web-1"},
+		{"nested field", "{.items[1].image.distribution}", testInstances, "debian"},
+		{"quoted field", "{.items[0]['hostname']}", testInstances, "web-1"},
+		{"map key", "{.items[0].tags.env}", testInstances, "prod"},
+		{"index", "{.items[0].ips[1]}", testInstances, "10.0.0.2"},
+		{"wildcard", "{.items[*].hostname}", testInstances, "web-1 web-2 db-1"},
+		{"struct as json", "{.items[0].image}", testInstances, `{"image":"ubuntu-22.04-x86_64","distribution":"ubuntu"}`},
+		{"literal text", `id: {.items[2].cloudid}{"\n"}`, testInstances, "id: 3\n"},
+		{"single quoted literal", "{'a \"b\"'}", testInstances, `a "b"`},
+
+		{"negative index", "{.items[-1].hostname}", testInstances, "db-1"},
+		{"slice", "{.items[0:2].cloudid}", testInstances, "1 2"},
+		{"open slice", "{.items[1:].cloudid}", testInstances, "2 3"},
+		{"negative slice end", "{.items[:-1].cloudid}", testInstances, "1 2"},
+		{"negative slice start", "{.items[-2:].cloudid}", testInstances, "2 3"},
+		{"slice out of range", "{.items[5:].cloudid}", testInstances, ""},
+
+		{"filter", `{.items[?(@.status=="Active")].hostname}`, testInstances, "web-1 db-1"},
+		{"filter not equal", "{.items[?(@.status!='Active')].hostname}", testInstances, "web-2"},
+		{"filter nested field", `{.items[?(@.image.distribution=="debian")].cloudid}`, testInstances, "2"},
+		{"filter missing map key", `{.items[?(@.tags.env=="prod")].cloudid}`, testInstances, "1"},
+		{"filter no match", `{.items[?(@.status=="Deleted")].cloudid}`, testInstances, ""},
+
+		{"range", `{range .items[*]}{.hostname}={.status}{"\n"}{end}`, testInstances, "web-1=Active\nweb-2=Stopped\ndb-1=Active\n"},
+		{"range filter", `{range .items[?(@.status=="Active")]}[{.cloudid}]{end}`, testInstances, "[1][3]"},
+		{"nested range", "{range .items[0:1]}{range .ips[*]}<{@}>{end}{end}", testInstances, "<10.0.0.1><10.0.0.2>"},
+
+		{"items of a single object", "{.items[*].hostname}", testInstances[0], "web-1"},
+		{"items of a pointer", "{.items[0].cloudid}", &testInstances[1], "2"},
+		{"field of a single object", "{.hostname}", testInstances[2], "db-1"},
+		{"items field wins", "{.items[1]}", testList{Items: []string{"a", "b"}}, "b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := printJSONPath(tt.tmpl, tt.data)
+			if err != nil {
+				t.Fatalf("jsonpath %s: %v", tt.tmpl, err)
+			}
+			if got != tt.want {
+				t.Errorf("jsonpath %s = %q, want %q", tt.tmpl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPathErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		tmpl string
+		data any
+		want string
+	}{
+		{"empty", "", testInstances, "missing template, use -o jsonpath='{.items[*].ID}'"},
+		{"unclosed expression", "{.hostname", testInstances, `jsonpath: unclosed expression in "{.hostname"`},
+		{"end without range", "{.hostname}{end}", testInstances, "jsonpath: {end} without {range}"},
+		{"range without end", "{range .items[*]}{.hostname}", testInstances, "jsonpath: {range} without {end}"},
+		{"unclosed bracket", "{.items[0}", testInstances, `jsonpath: unclosed [ in ".items[0"`},
+		{"invalid index", "{.items[a]}", testInstances, `jsonpath: invalid index "a" in ".items[a]"`},
+		{"invalid slice", "{.items[1:x]}", testInstances, `jsonpath: invalid slice "1:x" in ".items[1:x]"`},
+		{"unsupported filter", "{.items[?(@.status>1)]}", testInstances, `jsonpath: unsupported filter "@.status>1" in ".items[?(@.status>1)]"`},
+		{"missing dot", "{hostname}", testInstances, `jsonpath: unexpected 'h' in "hostname"`},
+		{"invalid literal", `{"\q"}`, testInstances, `jsonpath: invalid literal "\q"`},
+
+		{"unknown field", "{.items[0].missing}", testInstances, `jsonpath: field "missing" not found`},
+		{"index out of range", "{.items[3]}", testInstances, "jsonpath: index 3 out of range"},
+		{"negative index out of range", "{.items[-4]}", testInstances, "jsonpath: index -4 out of range"},
+		{"index a string", "{.items[0].hostname[0]}", testInstances, "jsonpath: cannot index string"},
+		{"slice a string", "{.items[0].hostname[1:]}", testInstances, "jsonpath: cannot slice string"},
+		{"filter a struct", `{.items[0].image[?(@.image=="x")]}`, testInstances, "jsonpath: cannot filter struct"},
+		// The objects are only reachable as items from the root.
+		{"items in a range", "{range .items[*]}{.items[0]}{end}", testInstances, `jsonpath: field "items" not found`},
+		{"items of a field", "{.image.items}", testInstances[0], `jsonpath: field "items" not found`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := printJSONPath(tt.tmpl, tt.data)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("jsonpath %s: error %v, want %q", tt.tmpl, err, tt.want)
+			}
+		})
+	}
+}
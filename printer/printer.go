@@ -0,0 +1,198 @@
+// Package printer renders command results in the format selected with the
+// global --output flag.
+package printer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatTable      = "table"
+	FormatJSON       = "json"
+	FormatYAML       = "yaml"
+	FormatGoTemplate = "go-template"
+	FormatJSONPath   = "jsonpath"
+)
+
+// Formats lists the values accepted by --output. Template formats take their
+// template after an equal sign, eg: go-template={{.ID}}.
+var Formats = []string{FormatTable, FormatJSON, FormatYAML, FormatGoTemplate + "=...", FormatJSONPath + "=..."}
+
+type Printer struct {
+	Format string
+	Out    io.Writer
+
+	// Columns replaces the default table columns, Wide shows every scalar
+	// field instead. Both accept Go or API field names (see Fields).
+	Columns   []string
+	Wide      bool
+	NoHeaders bool
+	// SortBy orders lists by the given field in every format.
+	SortBy string
+	// KeepWidths keeps the table columns as wide as in the previous Print
+	// calls, for outputs printed a few rows at a time.
+	KeepWidths bool
+
+	widths []int
+
+	tmpl *template.Template
+	path *jsonPath
+}
+
+// New returns a Printer writing to out, or an error if format is unknown or
+// its template does not parse.
+func New(format string, out io.Writer) (*Printer, error) {
+	format, arg, hasArg := strings.Cut(strings.TrimSpace(format), "=")
+	format = strings.ToLower(format)
+	if format == "" {
+		format = FormatTable
+	}
+
+	p := &Printer{Format: format, Out: out}
+	switch format {
+	case FormatTable, FormatJSON, FormatYAML:
+		if hasArg {
+			return nil, fmt.Errorf("output format %q does not take a template", format)
+		}
+	case FormatGoTemplate:
+		if arg == "" {
+			return nil, fmt.Errorf("missing template, use -o go-template='{{.ID}}'")
+		}
+		tmpl, err := template.New("output").Funcs(templateFuncs).Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid go-template: %v", err)
+		}
+		p.tmpl = tmpl
+	case FormatJSONPath:
+		if arg == "" {
+			return nil, fmt.Errorf("missing template, use -o jsonpath='{.items[*].ID}'")
+		}
+		path, err := parseJSONPath(arg)
+		if err != nil {
+			return nil, err
+		}
+		p.path = path
+	default:
+		return nil, fmt.Errorf("unknown output format %q (supported: %s)", format, strings.Join(Formats, ", "))
+	}
+
+	return p, nil
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// ExprError is the error of an expression that does not apply to the
+// printed value, such as a go-template, jsonpath, column or --sort-by
+// naming a missing field.
+type ExprError struct {
+	Err error
+}
+
+func (e *ExprError) Error() string { return e.Err.Error() }
+
+func (e *ExprError) Unwrap() error { return e.Err }
+
+// Print writes v to the printer's output. v is usually a struct, a pointer to
+// a struct or a slice of them as returned by utho-go. columns names the
+// struct fields shown in table mode, nested fields are separated by a dot
+// (eg: Image.Image). Structured formats always include every field.
+func (p *Printer) Print(v any, columns ...string) error {
+	v = normalize(v)
+	if p.SortBy != "" {
+		sorted, err := sortBy(v, p.SortBy)
+		if err != nil {
+			return &ExprError{fmt.Errorf("invalid --sort-by: %v", err)}
+		}
+		v = sorted
+	}
+
+	switch p.Format {
+	case FormatJSON:
+		return p.printJSON(v)
+	case FormatYAML:
+		return p.printYAML(v)
+	case FormatGoTemplate:
+		return p.printGoTemplate(v)
+	case FormatJSONPath:
+		return p.path.execute(p.Out, v)
+	default:
+		return p.printTable(v, columns)
+	}
+}
+
+func (p *Printer) printJSON(v any) error {
+	enc := json.NewEncoder(p.Out)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+// printGoTemplate executes the template against the utho-go value, so fields
+// use their Go names (eg: {{.Image.Image}}). Lists are exposed as .items, the
+// same way the jsonpath format sees them.
+func (p *Printer) printGoTemplate(v any) error {
+	data := v
+	if isList(reflect.ValueOf(v)) {
+		data = map[string]any{"items": v}
+	}
+	err := p.tmpl.Execute(p.Out, data)
+	var execErr template.ExecError
+	if errors.As(err, &execErr) {
+		return &ExprError{err}
+	}
+	return err
+}
+
+// printYAML goes through JSON first so that keys match the API field names
+// declared in the utho-go json tags, and keeps their declaration order.
+func (p *Printer) printYAML(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	resetStyle(&node)
+
+	enc := yaml.NewEncoder(p.Out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// resetStyle drops the flow and quoting styles inherited from the JSON
+// document so the encoder emits block style YAML.
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// normalize turns nil slices into empty ones so that an empty list renders
+// as [] instead of null.
+func normalize(v any) any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice && rv.IsNil() {
+		return reflect.MakeSlice(rv.Type(), 0, 0).Interface()
+	}
+	return v
+}
@@ -0,0 +1,55 @@
+package printer
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// sortBy returns a copy of the slice v ordered by the field at path, or v
+// unchanged when it is not a slice. Values that parse as numbers on both
+// sides are compared numerically, the rest as strings.
+func sortBy(v any, path string) (any, error) {
+	rv := indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Slice {
+		return v, nil
+	}
+
+	resolved, err := resolveField(rv.Type(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+	reflect.Copy(sorted, rv)
+
+	keys := make([]string, sorted.Len())
+	for i := range keys {
+		keys[i] = field(sorted.Index(i), resolved)
+	}
+	swap := reflect.Swapper(sorted.Interface())
+	sort.Stable(byKey{keys: keys, swap: swap})
+
+	return sorted.Interface(), nil
+}
+
+type byKey struct {
+	keys []string
+	swap func(i, j int)
+}
+
+func (b byKey) Len() int { return len(b.keys) }
+
+func (b byKey) Swap(i, j int) {
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+	b.swap(i, j)
+}
+
+func (b byKey) Less(i, j int) bool {
+	x, errX := strconv.ParseFloat(b.keys[i], 64)
+	y, errY := strconv.ParseFloat(b.keys[j], 64)
+	if errX == nil && errY == nil {
+		return x < y
+	}
+	return b.keys[i] < b.keys[j]
+}
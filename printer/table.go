@@ -0,0 +1,170 @@
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rodaine/table"
+)
+
+func (p *Printer) printTable(v any, columns []string) error {
+	columns, err := p.tableColumns(v, columns)
+	if err != nil {
+		return err
+	}
+
+	headers := make([]interface{}, len(columns))
+	for i, header := range headerNames(columns) {
+		if p.NoHeaders {
+			header = ""
+		}
+		headers[i] = header
+	}
+
+	var values [][]interface{}
+	for _, item := range rows(v) {
+		row := make([]interface{}, len(columns))
+		for i, column := range columns {
+			row[i] = field(item, column)
+		}
+		values = append(values, row)
+	}
+	if p.KeepWidths {
+		p.padColumns(headers, values)
+	}
+
+	var buf bytes.Buffer
+	tbl := table.New(headers...).WithWriter(&buf)
+	for _, row := range values {
+		tbl.AddRow(row...)
+	}
+	tbl.Print()
+
+	out := buf.Bytes()
+	if p.NoHeaders {
+		// rodaine/table always prints a header line, drop the blank one.
+		if i := bytes.IndexByte(out, '\n'); i >= 0 {
+			out = out[i+1:]
+		}
+	}
+	_, err = p.Out.Write(out)
+	return err
+}
+
+// padColumns pads the cells to the widest cell of their column printed so
+// far, so the columns of successive tables line up.
+func (p *Printer) padColumns(headers []interface{}, values [][]interface{}) {
+	for len(p.widths) < len(headers) {
+		p.widths = append(p.widths, 0)
+	}
+	for _, row := range append([][]interface{}{headers}, values...) {
+		for i, cell := range row {
+			p.widths[i] = max(p.widths[i], len(fmt.Sprint(cell)))
+		}
+	}
+	for _, row := range append([][]interface{}{headers}, values...) {
+		for i, cell := range row {
+			row[i] = fmt.Sprintf("%-*s", p.widths[i], fmt.Sprint(cell))
+		}
+	}
+}
+
+// tableColumns picks the columns to show: the ones given with --columns,
+// every scalar field with --wide, or the command defaults.
+func (p *Printer) tableColumns(v any, defaults []string) ([]string, error) {
+	switch {
+	case len(p.Columns) > 0:
+		t := reflect.TypeOf(v)
+		columns := make([]string, len(p.Columns))
+		for i, column := range p.Columns {
+			resolved, err := resolveField(t, column)
+			if err != nil {
+				return nil, &ExprError{fmt.Errorf("invalid column: %v", err)}
+			}
+			columns[i] = resolved
+		}
+		return columns, nil
+	case p.Wide:
+		return Fields(v), nil
+	default:
+		return defaults, nil
+	}
+}
+
+// headerNames uses the last segment of each field path as its header, or
+// the whole path when two columns would end up with the same header.
+func headerNames(columns []string) []string {
+	count := map[string]int{}
+	for _, column := range columns {
+		count[lastSegment(column)]++
+	}
+
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = lastSegment(column)
+		if count[headers[i]] > 1 {
+			headers[i] = column
+		}
+	}
+	return headers
+}
+
+func lastSegment(path string) string {
+	return path[strings.LastIndex(path, ".")+1:]
+}
+
+// rows returns the elements of v when it is a slice, or v itself otherwise.
+func rows(v any) []reflect.Value {
+	rv := indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []reflect.Value{rv}
+	}
+
+	items := make([]reflect.Value, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i)
+	}
+	return items
+}
+
+// field resolves a dotted field path on a struct value and returns it ready
+// to be printed. Missing fields render as an empty cell, and the field of
+// the elements of a slice as their comma separated values.
+func field(v reflect.Value, path string) string {
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		v = indirect(v)
+		if v.Kind() == reflect.Slice && i > 0 {
+			values := make([]string, v.Len())
+			for j := range values {
+				values[j] = field(v.Index(j), strings.Join(names[i:], "."))
+			}
+			return strings.Join(values, ",")
+		}
+		if v.Kind() != reflect.Struct {
+			return ""
+		}
+		v = v.FieldByName(name)
+		if !v.IsValid() {
+			return ""
+		}
+	}
+
+	v = indirect(v)
+	if !v.IsValid() {
+		return ""
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}